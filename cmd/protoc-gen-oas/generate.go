@@ -0,0 +1,226 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/protoc-gen-oas/gen"
+)
+
+// bufImageExtension is field number of buf.alpha.image.v1.ImageFileExtension
+// in FileDescriptorProto of buf image.
+const bufImageExtension = 8042
+
+// patterns is comma-separated list of glob patterns.
+type patterns []string
+
+func (p *patterns) String() string { return strings.Join(*p, ",") }
+
+func (p *patterns) Set(s string) error {
+	for _, pattern := range strings.Split(s, ",") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "pattern %q", pattern)
+		}
+		*p = append(*p, pattern)
+	}
+	return nil
+}
+
+// match whether any of patterns matches name, empty list matches everything.
+func (p patterns) match(name string) bool {
+	if len(p) == 0 {
+		return true
+	}
+	for _, pattern := range p {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func runGenerate(args []string) error {
+	set := flag.NewFlagSet("generate", flag.ExitOnError)
+	set.Usage = func() {
+		_, _ = fmt.Fprintf(set.Output(), "Usage: %s generate --descriptor_set_in=FILE [flags]\n\n", os.Args[0])
+		set.PrintDefaults()
+	}
+
+	var (
+		params   gen.Params
+		inputs   []string
+		files    patterns
+		packages patterns
+		services patterns
+		output   string
+	)
+	params.RegisterFlags(set)
+	set.Func("descriptor_set_in", "FileDescriptorSet or buf image (binary or JSON), can be repeated", func(s string) error {
+		inputs = append(inputs, s)
+		return nil
+	})
+	set.Var(&files, "files", "Comma-separated glob patterns of proto file paths")
+	set.Var(&packages, "packages", "Comma-separated glob patterns of proto packages")
+	set.Var(&services, "services", "Comma-separated glob patterns of fully-qualified service names")
+	set.StringVar(&output, "o", "", "Output file, - for stdout (default is filename with format extension)")
+
+	if err := set.Parse(args); err != nil {
+		return errors.Wrap(err, "parse args")
+	}
+	if len(inputs) == 0 {
+		return errors.New("no --descriptor_set_in given")
+	}
+	if err := params.LoadConfig(); err != nil {
+		return err
+	}
+
+	fds := new(descriptorpb.FileDescriptorSet)
+	seen := make(map[string]struct{})
+	for _, input := range inputs {
+		data, err := os.ReadFile(input)
+		if err != nil {
+			return err
+		}
+		s, err := gen.UnmarshalFileDescriptorSet(data)
+		if err != nil {
+			return errors.Wrapf(err, "read %q", input)
+		}
+		for _, f := range s.GetFile() {
+			if _, ok := seen[f.GetName()]; ok {
+				continue
+			}
+			seen[f.GetName()] = struct{}{}
+			fds.File = append(fds.File, f)
+		}
+	}
+
+	paths, err := selectFiles(fds, files, packages)
+	if err != nil {
+		return err
+	}
+
+	opts, err := params.Options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, gen.WithServiceFilter(func(s *protogen.Service) bool {
+		return services.match(string(s.Desc.FullName()))
+	}))
+
+	g, err := gen.NewGeneratorFromFileDescriptorSet(fds, paths, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := params.Check(g, os.Stderr); err != nil {
+		return err
+	}
+
+	name, data, err := params.Output(g)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "-":
+		_, err = os.Stdout.Write(data)
+		return err
+	case "":
+		output = name
+	}
+	return os.WriteFile(output, data, 0o644)
+}
+
+// selectFiles returns paths of files to generate.
+//
+// Without file and package patterns, dependencies are skipped: files marked
+// as imports in buf image, or imported google/* files (well-known types and
+// googleapis) added by protoc --include_imports. Other imported files are
+// generated, as messages of service files are often defined in them.
+func selectFiles(set *descriptorpb.FileDescriptorSet, files, packages patterns) ([]string, error) {
+	explicit := len(files) > 0 || len(packages) > 0
+
+	imported := make(map[string]struct{})
+	for _, f := range set.GetFile() {
+		for _, dep := range f.GetDependency() {
+			imported[dep] = struct{}{}
+		}
+	}
+
+	var paths []string
+	for _, f := range set.GetFile() {
+		if !explicit {
+			if isImport, ok := bufImport(f); ok {
+				if isImport {
+					continue
+				}
+			} else if _, ok := imported[f.GetName()]; ok && strings.HasPrefix(f.GetName(), "google/") {
+				continue
+			}
+		}
+		if files.match(f.GetName()) && packages.match(f.GetPackage()) {
+			paths = append(paths, f.GetName())
+		}
+	}
+	if len(paths) == 0 {
+		return nil, errors.New("no files selected")
+	}
+	return paths, nil
+}
+
+// bufImport returns whether file is a dependency included into buf image,
+// ok is false if file has no buf image extension.
+func bufImport(f *descriptorpb.FileDescriptorProto) (isImport, ok bool) {
+	b := f.ProtoReflect().GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false, false
+		}
+		b = b[n:]
+
+		if num != bufImageExtension || typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return false, false
+			}
+			b = b[n:]
+			continue
+		}
+
+		ext, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return false, false
+		}
+		b = b[n:]
+
+		// is_import = 1.
+		for len(ext) > 0 {
+			num, typ, n := protowire.ConsumeTag(ext)
+			if n < 0 {
+				return false, true
+			}
+			ext = ext[n:]
+			if num == 1 && typ == protowire.VarintType {
+				v, n := protowire.ConsumeVarint(ext)
+				return n >= 0 && v != 0, true
+			}
+			n = protowire.ConsumeFieldValue(num, typ, ext)
+			if n < 0 {
+				return false, true
+			}
+			ext = ext[n:]
+		}
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// withBufImage returns copy of file with buf image extension encoded.
+func withBufImage(t *testing.T, f *descriptorpb.FileDescriptorProto, ext []byte) *descriptorpb.FileDescriptorProto {
+	t.Helper()
+
+	f = proto.Clone(f).(*descriptorpb.FileDescriptorProto)
+	var b []byte
+	// Unrelated unknown field goes first.
+	b = protowire.AppendTag(b, 9000, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	b = protowire.AppendTag(b, bufImageExtension, protowire.BytesType)
+	b = protowire.AppendBytes(b, ext)
+	f.ProtoReflect().SetUnknown(b)
+	return f
+}
+
+func isImportExt(v uint64) []byte {
+	var ext []byte
+	// Unrelated field of ImageFileExtension.
+	ext = protowire.AppendTag(ext, 2, protowire.BytesType)
+	ext = protowire.AppendString(ext, "buf.build/acme/service")
+	ext = protowire.AppendTag(ext, 1, protowire.VarintType)
+	return protowire.AppendVarint(ext, v)
+}
+
+func TestBufImport(t *testing.T) {
+	t.Parallel()
+
+	f := &descriptorpb.FileDescriptorProto{Name: proto.String("service.proto")}
+
+	tests := []struct {
+		name     string
+		file     *descriptorpb.FileDescriptorProto
+		isImport bool
+		ok       bool
+	}{
+		{"NoExtension", f, false, false},
+		{"Import", withBufImage(t, f, isImportExt(1)), true, true},
+		{"NotImport", withBufImage(t, f, isImportExt(0)), false, true},
+		{"NoIsImport", withBufImage(t, f, nil), false, true},
+		{"Malformed", withBufImage(t, f, []byte{0xff}), false, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			isImport, ok := bufImport(tt.file)
+			require.Equal(t, tt.isImport, isImport)
+			require.Equal(t, tt.ok, ok)
+		})
+	}
+
+	t.Run("Truncated", func(t *testing.T) {
+		f := proto.Clone(f).(*descriptorpb.FileDescriptorProto)
+		f.ProtoReflect().SetUnknown(protowire.AppendTag(nil, bufImageExtension, protowire.BytesType))
+
+		isImport, ok := bufImport(f)
+		require.False(t, isImport)
+		require.False(t, ok)
+	})
+}
+
+// testSet returns descriptor set of service.proto with all its dependencies,
+// as protoc --include_imports produces.
+func testSet(t *testing.T) *descriptorpb.FileDescriptorSet {
+	t.Helper()
+
+	messages := new(descriptorpb.FileDescriptorProto)
+	require.NoError(t, prototext.Unmarshal([]byte(`
+		name: "service/v1/messages.proto"
+		package: "service.v1"
+		syntax: "proto3"
+		message_type: {
+			name: "Item"
+			field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+		}
+	`), messages))
+
+	service := new(descriptorpb.FileDescriptorProto)
+	require.NoError(t, prototext.Unmarshal([]byte(`
+		name: "service/v1/service.proto"
+		package: "service.v1"
+		dependency: "google/api/annotations.proto"
+		dependency: "service/v1/messages.proto"
+		syntax: "proto3"
+		service: {
+			name: "Service"
+			method: {
+				name: "GetItem"
+				input_type: ".service.v1.Item"
+				output_type: ".service.v1.Item"
+				options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+			}
+		}
+		service: {
+			name: "AdminService"
+			method: {
+				name: "DeleteItem"
+				input_type: ".service.v1.Item"
+				output_type: ".service.v1.Item"
+				options: { [google.api.http]: { delete: "/admin/v1/items/{id}" } }
+			}
+		}
+	`), service))
+
+	set := new(descriptorpb.FileDescriptorSet)
+	seen := make(map[string]struct{})
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if _, ok := seen[fd.Path()]; ok {
+			return
+		}
+		seen[fd.Path()] = struct{}{}
+		for i := 0; i < fd.Imports().Len(); i++ {
+			add(fd.Imports().Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(annotations.File_google_api_annotations_proto)
+	set.File = append(set.File, messages, service)
+	return set
+}
+
+func TestSelectFiles(t *testing.T) {
+	t.Parallel()
+
+	set := testSet(t)
+
+	tests := []struct {
+		name     string
+		files    patterns
+		packages patterns
+		want     []string
+		wantErr  string
+	}{
+		{
+			// Imported messages.proto is not a google/* dependency.
+			name: "Default",
+			want: []string{"service/v1/messages.proto", "service/v1/service.proto"},
+		},
+		{
+			name:  "Files",
+			files: patterns{"google/api/*.proto"},
+			want:  []string{"google/api/http.proto", "google/api/annotations.proto"},
+		},
+		{
+			name:     "Packages",
+			packages: patterns{"google.*"},
+			want: []string{
+				"google/api/http.proto",
+				"google/protobuf/descriptor.proto",
+				"google/api/annotations.proto",
+			},
+		},
+		{
+			name:     "FilesAndPackages",
+			files:    patterns{"google/*/*.proto"},
+			packages: patterns{"google.protobuf"},
+			want:     []string{"google/protobuf/descriptor.proto"},
+		},
+		{
+			name:    "NoFiles",
+			files:   patterns{"unknown/*.proto"},
+			wantErr: "no files selected",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := selectFiles(set, tt.files, tt.packages)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	t.Run("BufImage", func(t *testing.T) {
+		t.Parallel()
+
+		image := proto.Clone(set).(*descriptorpb.FileDescriptorSet)
+		for i, f := range image.File {
+			isImport := uint64(1)
+			if f.GetName() == "service/v1/service.proto" || f.GetName() == "google/api/http.proto" {
+				// Part of the module, though imported.
+				isImport = 0
+			}
+			image.File[i] = withBufImage(t, f, isImportExt(isImport))
+		}
+
+		got, err := selectFiles(image, nil, nil)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"google/api/http.proto", "service/v1/service.proto"}, got)
+	})
+}
+
+func TestRunGenerate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	set := testSet(t)
+
+	binary, err := proto.Marshal(set)
+	require.NoError(t, err)
+	binaryPath := filepath.Join(dir, "image.binpb")
+	require.NoError(t, os.WriteFile(binaryPath, binary, 0o600))
+
+	json, err := protojson.Marshal(set)
+	require.NoError(t, err)
+	jsonPath := filepath.Join(dir, "image.json")
+	require.NoError(t, os.WriteFile(jsonPath, json, 0o600))
+
+	for _, input := range []string{binaryPath, jsonPath} {
+		input := input
+		t.Run(filepath.Ext(input), func(t *testing.T) {
+			t.Parallel()
+
+			output := filepath.Join(t.TempDir(), "openapi.yaml")
+			require.NoError(t, runGenerate([]string{
+				"--descriptor_set_in=" + input,
+				"--services=service.v1.Service",
+				"-o", output,
+			}))
+
+			data, err := os.ReadFile(output)
+			require.NoError(t, err)
+			spec := string(data)
+			require.Contains(t, spec, "/api/v1/items/{id}")
+			require.NotContains(t, spec, "/admin/v1/items/{id}")
+			// Schema of message from imported file is generated.
+			require.Contains(t, spec, "\n    Item:\n")
+			// Dependencies are not generated.
+			require.NotContains(t, spec, "FieldBehavior")
+			require.NotContains(t, spec, "Edition")
+		})
+	}
+
+	t.Run("NoFiles", func(t *testing.T) {
+		t.Parallel()
+
+		err := runGenerate([]string{
+			"--descriptor_set_in=" + binaryPath,
+			"--packages=unknown.*",
+			"-o", filepath.Join(t.TempDir(), "openapi.yaml"),
+		})
+		require.EqualError(t, err, "no files selected")
+	})
+
+	t.Run("NoInput", func(t *testing.T) {
+		t.Parallel()
+
+		require.EqualError(t, runGenerate(nil), "no --descriptor_set_in given")
+	})
+}
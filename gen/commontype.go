@@ -0,0 +1,80 @@
+package gen
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/ogen-go/ogen"
+)
+
+// Formats of well-known types as protojson encodes them.
+const (
+	durationDescription  = "Duration in seconds with up to 9 fractional digits and \"s\" suffix, e.g. `1.5s`."
+	timestampDescription = "RFC 3339 date-time, e.g. `2017-01-15T01:30:15.01Z`. Output is normalized to UTC with \"Z\" suffix and 0, 3, 6 or 9 fractional digits, offsets are accepted in input."
+)
+
+// durationPattern matches protojson encoding of google.protobuf.Duration.
+const durationPattern = `^-?[0-9]+(\.[0-9]{1,9})?s$`
+
+// refineCommonType adds constraints of googleapis common types to message schema.
+//
+// See https://github.com/googleapis/googleapis/tree/master/google/type.
+func refineCommonType(md protoreflect.MessageDescriptor, s *ogen.Schema) {
+	if md.FullName().Parent() != "google.type" {
+		return
+	}
+
+	describe := func(description string) {
+		if s.Description == "" {
+			s.SetDescription(description)
+		}
+	}
+	switch md.Name() {
+	case "Date":
+		describe("Calendar date, year, month or day may be 0 for partial dates.")
+		setPropertyRange(s, "year", "0", "9999")
+		setPropertyRange(s, "month", "0", "12")
+		setPropertyRange(s, "day", "0", "31")
+	case "TimeOfDay":
+		describe("Time of day in 24 hour format.")
+		setPropertyRange(s, "hours", "0", "24")
+		setPropertyRange(s, "minutes", "0", "59")
+		setPropertyRange(s, "seconds", "0", "60")
+		setPropertyRange(s, "nanos", "0", "999999999")
+	case "Money":
+		describe("Amount of money with its currency type.")
+		if p := findProperty(s, "currencyCode"); p != nil {
+			// ISO 4217 currency code.
+			p.SetPattern("^[A-Z]{3}$")
+		}
+		setPropertyRange(s, "nanos", "-999999999", "999999999")
+	case "LatLng":
+		describe("Latitude and longitude pair in degrees, WGS84 standard.")
+		setPropertyRange(s, "latitude", "-90", "90")
+		setPropertyRange(s, "longitude", "-180", "180")
+	case "Color":
+		describe("Color in the RGBA color space.")
+		for _, name := range []string{"red", "green", "blue", "alpha"} {
+			setPropertyRange(s, name, "0", "1")
+		}
+	case "Interval":
+		describe("Time interval, start is inclusive and end is exclusive.")
+	}
+}
+
+func findProperty(s *ogen.Schema, name string) *ogen.Schema {
+	for _, p := range s.Properties {
+		if p.Name == name {
+			return p.Schema
+		}
+	}
+	return nil
+}
+
+func setPropertyRange(s *ogen.Schema, name, minimum, maximum string) {
+	p := findProperty(s, name)
+	if p == nil {
+		return
+	}
+	p.Minimum = ogen.Num(minimum)
+	p.Maximum = ogen.Num(maximum)
+}
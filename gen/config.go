@@ -0,0 +1,407 @@
+package gen
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+)
+
+// Config is generator configuration file.
+//
+// Both YAML and JSON files are accepted, unknown keys are errors.
+// Relative paths of base document, patches and examples are resolved
+// against the directory of configuration file.
+type Config struct {
+	OpenAPI             string                     `yaml:"openapi"`
+	Info                ConfigInfo                 `yaml:"info"`
+	Servers             []ConfigServer             `yaml:"servers"`
+	SecuritySchemes     map[string]*ConfigSecurity `yaml:"security_schemes"`
+	Security            ogen.SecurityRequirements  `yaml:"security"`
+	Naming              ConfigNaming               `yaml:"naming"`
+	Visibility          ConfigVisibility           `yaml:"visibility"`
+	FieldMask           ConfigFieldMask            `yaml:"field_mask"`
+	Pagination          ConfigPagination           `yaml:"pagination"`
+	Longrunning         ConfigLongrunning          `yaml:"longrunning"`
+	Output              ConfigOutput               `yaml:"output"`
+	Streaming           ConfigStreaming            `yaml:"streaming"`
+	QueryRecursionLimit *int                       `yaml:"query_recursion_limit"`
+	SharedParameters    *int                       `yaml:"shared_parameters"`
+	Services            map[string]ConfigService   `yaml:"services"`
+	Base                ConfigBase                 `yaml:"base"`
+	// Patches are paths to Overlay documents or JSON Patch files.
+	Patches []string `yaml:"patches"`
+	// Examples is path to directory with message example files.
+	Examples string     `yaml:"examples"`
+	Lint     ConfigLint `yaml:"lint"`
+}
+
+// ConfigLint is lint settings.
+type ConfigLint struct {
+	Level   LintLevel  `yaml:"level"`
+	Enable  []LintRule `yaml:"enable"`
+	Disable []LintRule `yaml:"disable"`
+}
+
+// ConfigBase is base document settings.
+type ConfigBase struct {
+	// File is path to OpenAPI document generated paths and components are merged into.
+	File     string      `yaml:"file"`
+	Conflict MergePolicy `yaml:"conflict"`
+}
+
+// ConfigInfo is API information.
+type ConfigInfo struct {
+	Title          string         `yaml:"title"`
+	Summary        string         `yaml:"summary"`
+	Description    string         `yaml:"description"`
+	TermsOfService string         `yaml:"terms_of_service"`
+	Contact        ConfigContact  `yaml:"contact"`
+	License        ConfigLicense  `yaml:"license"`
+	Version        string         `yaml:"version"`
+	Logo           string         `yaml:"logo"`
+	Extensions     map[string]any `yaml:"extensions"`
+}
+
+// ConfigContact is API contact.
+type ConfigContact struct {
+	Name  string `yaml:"name"`
+	URL   string `yaml:"url"`
+	Email string `yaml:"email"`
+}
+
+// ConfigLicense is API license.
+type ConfigLicense struct {
+	Name       string `yaml:"name"`
+	Identifier string `yaml:"identifier"`
+	URL        string `yaml:"url"`
+}
+
+// ConfigServer is API server.
+type ConfigServer struct {
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+}
+
+// ConfigSecurity is security scheme.
+type ConfigSecurity struct {
+	Type             string            `yaml:"type"`
+	Description      string            `yaml:"description"`
+	Name             string            `yaml:"name"`
+	In               string            `yaml:"in"`
+	Scheme           string            `yaml:"scheme"`
+	BearerFormat     string            `yaml:"bearer_format"`
+	Flows            *ConfigOAuthFlows `yaml:"flows"`
+	OpenIDConnectURL string            `yaml:"openid_connect_url"`
+}
+
+// ConfigOAuthFlows are OAuth flows of security scheme.
+type ConfigOAuthFlows struct {
+	Implicit          *ConfigOAuthFlow `yaml:"implicit"`
+	Password          *ConfigOAuthFlow `yaml:"password"`
+	ClientCredentials *ConfigOAuthFlow `yaml:"client_credentials"`
+	AuthorizationCode *ConfigOAuthFlow `yaml:"authorization_code"`
+}
+
+// ConfigOAuthFlow is OAuth flow.
+type ConfigOAuthFlow struct {
+	AuthorizationURL string            `yaml:"authorization_url"`
+	TokenURL         string            `yaml:"token_url"`
+	RefreshURL       string            `yaml:"refresh_url"`
+	Scopes           map[string]string `yaml:"scopes"`
+}
+
+// ConfigNaming is naming settings.
+type ConfigNaming struct {
+	OperationID OperationIDNaming `yaml:"operation_id"`
+	FieldOrder  FieldOrder        `yaml:"field_order"`
+}
+
+// ConfigVisibility is visibility settings.
+type ConfigVisibility struct {
+	// Labels are selected restrictions of google.api.visibility rules.
+	Labels []string `yaml:"labels"`
+}
+
+// ConfigFieldMask is google.protobuf.FieldMask settings.
+type ConfigFieldMask struct {
+	// Paths enables listing of valid paths in x-field-mask-paths.
+	Paths bool `yaml:"paths"`
+}
+
+// ConfigPagination is AIP-158 pagination settings.
+type ConfigPagination struct {
+	// MaxPageSize is maximum of page_size field.
+	MaxPageSize *int `yaml:"max_page_size"`
+}
+
+// ConfigLongrunning is google.longrunning settings.
+type ConfigLongrunning struct {
+	// OperationsPrefix enables GetOperation and ListOperations paths under prefix, e.g. "/v1".
+	OperationsPrefix string `yaml:"operations_prefix"`
+}
+
+// ConfigOutput is output file settings.
+type ConfigOutput struct {
+	Format   string `yaml:"format"`
+	Indent   *int   `yaml:"indent"`
+	Filename string `yaml:"filename"`
+}
+
+// ConfigStreaming is streaming methods settings.
+type ConfigStreaming struct {
+	ContentType string          `yaml:"content_type"`
+	Policy      StreamingPolicy `yaml:"policy"`
+}
+
+// ConfigService is per-service settings, keyed by fully-qualified service name.
+type ConfigService struct {
+	// Skip excludes service from specification.
+	Skip     bool                      `yaml:"skip"`
+	Tags     []string                  `yaml:"tags"`
+	Security ogen.SecurityRequirements `yaml:"security"`
+}
+
+// LoadConfig reads configuration file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := ParseConfig(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "config %q", path)
+	}
+	c.resolvePaths(filepath.Dir(path))
+	return c, nil
+}
+
+// resolvePaths makes relative file paths of configuration relative to dir.
+func (c *Config) resolvePaths(dir string) {
+	resolve := func(path *string) {
+		if *path != "" && !filepath.IsAbs(*path) {
+			*path = filepath.Join(dir, *path)
+		}
+	}
+	resolve(&c.Base.File)
+	resolve(&c.Examples)
+	for i := range c.Patches {
+		resolve(&c.Patches[i])
+	}
+}
+
+// ParseConfig decodes and validates configuration.
+func ParseConfig(data []byte) (*Config, error) {
+	c := new(Config)
+
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	d.KnownFields(true)
+	if err := d.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Wrap(err, "decode")
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) validate() error {
+	for key := range c.Info.Extensions {
+		if !strings.HasPrefix(key, "x-") {
+			return errors.Errorf("info.extensions: extension %q must start with \"x-\"", key)
+		}
+	}
+	for i, s := range c.Servers {
+		if s.URL == "" {
+			return errors.Errorf("servers[%d]: url is required", i)
+		}
+	}
+	for name, s := range c.SecuritySchemes {
+		if s == nil {
+			return errors.Errorf("security_schemes.%s: scheme is empty", name)
+		}
+		switch s.Type {
+		case "apiKey", "http", "mutualTLS", "oauth2", "openIdConnect":
+		default:
+			return errors.Errorf("security_schemes.%s: unknown type %q", name, s.Type)
+		}
+	}
+	for i, req := range c.Security {
+		for name := range req {
+			if _, ok := c.SecuritySchemes[name]; !ok {
+				return errors.Errorf("security[%d]: unknown security scheme %q", i, name)
+			}
+		}
+	}
+	if n := c.Pagination.MaxPageSize; n != nil && *n <= 0 {
+		return errors.Errorf("pagination.max_page_size: must be positive, got %d", *n)
+	}
+	if n := c.SharedParameters; n != nil && *n < 0 {
+		return errors.Errorf("shared_parameters: must not be negative, got %d", *n)
+	}
+	if p := c.Longrunning.OperationsPrefix; p != "" && (!strings.HasPrefix(p, "/") || strings.HasSuffix(p, "/")) {
+		return errors.Errorf("longrunning.operations_prefix: must start and must not end with '/', got %q", p)
+	}
+	switch c.Naming.OperationID {
+	case "", OperationIDNamingMethod, OperationIDNamingServiceMethod:
+	default:
+		return errors.Errorf("naming.operation_id: unknown naming %q", c.Naming.OperationID)
+	}
+	for name, s := range c.Services {
+		for i, req := range s.Security {
+			for scheme := range req {
+				if _, ok := c.SecuritySchemes[scheme]; !ok {
+					return errors.Errorf("services.%s.security[%d]: unknown security scheme %q", name, i, scheme)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// apply sets parameters which are not set explicitly from configuration.
+func (c *Config) apply(p *Params) {
+	set := func(name string, to *string, value string) {
+		if value != "" && !p.explicit(name, *to == "") {
+			*to = value
+		}
+	}
+	set("openapi", &p.OpenAPI, c.OpenAPI)
+	set("title", &p.Title, c.Info.Title)
+	set("description", &p.Description, c.Info.Description)
+	set("version", &p.Version, c.Info.Version)
+	set("summary", &p.Summary, c.Info.Summary)
+	set("terms_of_service", &p.TermsOfService, c.Info.TermsOfService)
+	set("contact_name", &p.ContactName, c.Info.Contact.Name)
+	set("contact_url", &p.ContactURL, c.Info.Contact.URL)
+	set("contact_email", &p.ContactEmail, c.Info.Contact.Email)
+	set("license_name", &p.LicenseName, c.Info.License.Name)
+	set("license_identifier", &p.LicenseIdentifier, c.Info.License.Identifier)
+	set("license_url", &p.LicenseURL, c.Info.License.URL)
+	set("logo_url", &p.LogoURL, c.Info.Logo)
+	set("base", &p.Base, c.Base.File)
+	set("base_conflict", &p.BaseConflict, string(c.Base.Conflict))
+	set("examples", &p.Examples, c.Examples)
+	set("format", &p.Format, c.Output.Format)
+	set("filename", &p.Filename, c.Output.Filename)
+	set("stream_content_type", &p.StreamContentType, c.Streaming.ContentType)
+	set("streaming", &p.Streaming, string(c.Streaming.Policy))
+	set("field_order", &p.FieldOrder, string(c.Naming.FieldOrder))
+	set("lint", &p.Lint, string(c.Lint.Level))
+	if c.Lint.Enable != nil && !p.explicit("lint_enable", len(p.LintEnable) == 0) {
+		p.LintEnable = nil
+		for _, rule := range c.Lint.Enable {
+			p.LintEnable = append(p.LintEnable, string(rule))
+		}
+	}
+	if c.Lint.Disable != nil && !p.explicit("lint_disable", len(p.LintDisable) == 0) {
+		p.LintDisable = nil
+		for _, rule := range c.Lint.Disable {
+			p.LintDisable = append(p.LintDisable, string(rule))
+		}
+	}
+	if c.Patches != nil && !p.explicit("patch", len(p.Patches) == 0) {
+		p.Patches = c.Patches
+	}
+	if c.Output.Indent != nil && !p.explicit("indent", p.Indent == 0) {
+		p.Indent = *c.Output.Indent
+	}
+	if c.QueryRecursionLimit != nil && !p.explicit("query_recursion_limit", p.QueryRecursionLimit == 0) {
+		p.QueryRecursionLimit = *c.QueryRecursionLimit
+	}
+	if c.SharedParameters != nil && !p.explicit("shared_parameters", p.SharedParameters == 0) {
+		p.SharedParameters = *c.SharedParameters
+	}
+}
+
+// options returns generator options for settings not covered by parameters.
+func (c *Config) options() []GeneratorOption {
+	var opts []GeneratorOption
+
+	for key, value := range c.Info.Extensions {
+		opts = append(opts, WithSpecInfoExtension(key, value))
+	}
+	for _, s := range c.Servers {
+		opts = append(opts, WithSpecServers(ogen.NewServer().SetURL(s.URL).SetDescription(s.Description)))
+	}
+	for name, s := range c.SecuritySchemes {
+		opts = append(opts, WithSpecSecurityScheme(name, s.scheme()))
+	}
+	if c.Security != nil {
+		opts = append(opts, WithSpecSecurity(c.Security))
+	}
+	if c.Naming.OperationID != "" {
+		opts = append(opts, WithOperationIDNaming(c.Naming.OperationID))
+	}
+	if c.Visibility.Labels != nil {
+		opts = append(opts, WithVisibilityLabels(c.Visibility.Labels...))
+	}
+	if c.Pagination.MaxPageSize != nil {
+		opts = append(opts, WithMaxPageSize(*c.Pagination.MaxPageSize))
+	}
+	if c.FieldMask.Paths {
+		opts = append(opts, WithFieldMaskPaths(true))
+	}
+	if p := c.Longrunning.OperationsPrefix; p != "" {
+		opts = append(opts, WithOperationsPaths(p))
+	}
+	for name, s := range c.Services {
+		name := protoreflect.FullName(name)
+		if s.Skip {
+			opts = append(opts, WithServiceFilter(func(s *protogen.Service) bool {
+				return s.Desc.FullName() != name
+			}))
+			continue
+		}
+		opts = append(opts, WithServiceOverride(name, ServiceOverride{
+			Tags:     s.Tags,
+			Security: s.Security,
+		}))
+	}
+
+	return opts
+}
+
+func (s *ConfigSecurity) scheme() *ogen.SecurityScheme {
+	scheme := &ogen.SecurityScheme{
+		Type:             s.Type,
+		Description:      s.Description,
+		Name:             s.Name,
+		In:               s.In,
+		Scheme:           s.Scheme,
+		BearerFormat:     s.BearerFormat,
+		OpenIDConnectURL: s.OpenIDConnectURL,
+	}
+	if f := s.Flows; f != nil {
+		scheme.Flows = &ogen.OAuthFlows{
+			Implicit:          f.Implicit.flow(),
+			Password:          f.Password.flow(),
+			ClientCredentials: f.ClientCredentials.flow(),
+			AuthorizationCode: f.AuthorizationCode.flow(),
+		}
+	}
+	return scheme
+}
+
+func (f *ConfigOAuthFlow) flow() *ogen.OAuthFlow {
+	if f == nil {
+		return nil
+	}
+	return &ogen.OAuthFlow{
+		AuthorizationURL: f.AuthorizationURL,
+		TokenURL:         f.TokenURL,
+		RefreshURL:       f.RefreshURL,
+		Scopes:           f.Scopes,
+	}
+}
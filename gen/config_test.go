@@ -0,0 +1,89 @@
+package gen
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfigError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"UnknownKey", "info:\n  titel: API\n", `field "titel" not found`},
+		{"InvalidType", "output:\n  indent: two\n", "cannot unmarshal"},
+		{"ServerURL", "servers:\n  - description: Production\n", "servers[0]: url is required"},
+		{"SecuritySchemeType", "security_schemes:\n  key:\n    type: token\n", `security_schemes.key: unknown type "token"`},
+		{"UnknownSecurityScheme", "security:\n  - key: []\n", `security[0]: unknown security scheme "key"`},
+		{"OperationIDNaming", "naming:\n  operation_id: snake\n", `naming.operation_id: unknown naming "snake"`},
+		{"MaxPageSize", "pagination:\n  max_page_size: 0\n", "pagination.max_page_size: must be positive, got 0"},
+		{"SharedParameters", "shared_parameters: -1\n", "shared_parameters: must not be negative, got -1"},
+		{"OperationsPrefix", "longrunning:\n  operations_prefix: v1/\n", `longrunning.operations_prefix: must start and must not end with '/', got "v1/"`},
+		{
+			"UnknownServiceSecurityScheme",
+			"services:\n  service.v1.Service:\n    security:\n      - key: []\n",
+			`services.service.v1.Service.security[0]: unknown security scheme "key"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseConfig([]byte(tt.input))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	t.Parallel()
+
+	c, err := ParseConfig([]byte(`{"info": {"title": "Config", "version": "1.0.0"}, "output": {"indent": 4, "format": "json"}}`))
+	require.NoError(t, err)
+
+	var p Params
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	p.RegisterFlags(set)
+	require.NoError(t, set.Parse([]string{"-title=Params", "-indent=2"}))
+
+	c.apply(&p)
+	require.Equal(t, "Params", p.Title)
+	require.Equal(t, "1.0.0", p.Version)
+	require.Equal(t, 2, p.Indent)
+	require.Equal(t, FormatJSON, p.Format)
+
+	// Without flag set, non-zero parameters are explicit.
+	p = Params{Title: "Params"}
+	c.apply(&p)
+	require.Equal(t, "Params", p.Title)
+	require.Equal(t, 4, p.Indent)
+}
+
+func TestLoadConfigPaths(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	abs := filepath.Join(dir, "abs.yaml")
+	config := filepath.Join(dir, "oas.yaml")
+	require.NoError(t, os.WriteFile(config, []byte(`base:
+  file: base.yaml
+patches:
+  - patches/overlay.yaml
+  - `+abs+`
+examples: examples
+`), 0o600))
+
+	c, err := LoadConfig(config)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "base.yaml"), c.Base.File)
+	require.Equal(t, []string{filepath.Join(dir, "patches", "overlay.yaml"), abs}, c.Patches)
+	require.Equal(t, filepath.Join(dir, "examples"), c.Examples)
+}
@@ -0,0 +1,115 @@
+package gen
+
+import (
+	"bytes"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/go-faster/errors"
+)
+
+// NewGeneratorFromDescriptors returns new Generator instance for given files.
+//
+// Dependencies of files are resolved through descriptors, so files from
+// protoregistry or a descriptor set can be used without protoc.
+func NewGeneratorFromDescriptors(files []protoreflect.FileDescriptor, opts ...GeneratorOption) (*Generator, error) {
+	req := &pluginpb.CodeGeneratorRequest{}
+
+	var (
+		params []string
+		seen   = make(map[string]struct{})
+		add    func(fd protoreflect.FileDescriptor)
+	)
+	// Files must be listed in topological order, dependencies first.
+	add = func(fd protoreflect.FileDescriptor) {
+		if _, ok := seen[fd.Path()]; ok {
+			return
+		}
+		seen[fd.Path()] = struct{}{}
+
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+
+		fdp := protodesc.ToFileDescriptorProto(fd)
+		if fdp.GetOptions().GetGoPackage() == "" {
+			// protogen requires Go import path of every file, it is not used by generator.
+			params = append(params, "M"+fd.Path()+"="+placeholderImportPath(fd.Path()))
+		}
+		req.ProtoFile = append(req.ProtoFile, fdp)
+	}
+
+	for _, fd := range files {
+		add(fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+	}
+	if len(params) > 0 {
+		req.Parameter = proto.String(strings.Join(params, ","))
+	}
+
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "load files")
+	}
+
+	return NewGenerator(plugin.Files, opts...)
+}
+
+// NewGeneratorFromFileDescriptorSet returns new Generator instance for files
+// from descriptor set, e.g. produced by protoc --descriptor_set_out or buf build.
+//
+// If paths are empty, all files of the set are used.
+func NewGeneratorFromFileDescriptorSet(set *descriptorpb.FileDescriptorSet, paths []string, opts ...GeneratorOption) (*Generator, error) {
+	registry, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve descriptors")
+	}
+
+	var files []protoreflect.FileDescriptor
+	if len(paths) == 0 {
+		for _, fdp := range set.GetFile() {
+			paths = append(paths, fdp.GetName())
+		}
+	}
+	for _, p := range paths {
+		fd, err := registry.FindFileByPath(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "find file %q", p)
+		}
+		files = append(files, fd)
+	}
+
+	return NewGeneratorFromDescriptors(files, opts...)
+}
+
+// UnmarshalFileDescriptorSet decodes descriptor set in binary or JSON encoding.
+//
+// Buf images are accepted as well, image-specific fields are ignored.
+func UnmarshalFileDescriptorSet(data []byte) (*descriptorpb.FileDescriptorSet, error) {
+	set := new(descriptorpb.FileDescriptorSet)
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if err := opts.Unmarshal(data, set); err != nil {
+			return nil, errors.Wrap(err, "unmarshal json")
+		}
+		return set, nil
+	}
+
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, errors.Wrap(err, "unmarshal binary")
+	}
+	return set, nil
+}
+
+func placeholderImportPath(path string) string {
+	return "protoc-gen-oas.invalid/" + strings.TrimSuffix(path, ".proto") + ";pb"
+}
@@ -0,0 +1,131 @@
+package gen
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/ogen-go/ogen"
+)
+
+func TestNewGeneratorFromFileDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	for _, fileName := range []string{"crud", "external_message", "enum_values"} {
+		fileName := fileName
+
+		t.Run(fileName, func(t *testing.T) {
+			t.Parallel()
+
+			textproto, err := os.ReadFile(fmt.Sprintf("_testdata/%s.textproto", fileName))
+			require.NoError(t, err)
+
+			req := new(pluginpb.CodeGeneratorRequest)
+			require.NoError(t, prototext.Unmarshal(textproto, req))
+
+			p, err := protogen.Options{}.New(req)
+			require.NoError(t, err)
+			for _, f := range p.Files {
+				f.Generate = true
+			}
+
+			want, err := NewGenerator(p.Files)
+			require.NoError(t, err)
+			wantYAML, err := want.YAML()
+			require.NoError(t, err)
+
+			set := &descriptorpb.FileDescriptorSet{File: req.GetProtoFile()}
+			g, err := NewGeneratorFromFileDescriptorSet(set, nil)
+			require.NoError(t, err)
+			gotYAML, err := g.YAML()
+			require.NoError(t, err)
+
+			require.Equal(t, string(wantYAML), string(gotYAML))
+		})
+	}
+}
+
+func TestNewGeneratorFromFileDescriptorSetNoGoPackage(t *testing.T) {
+	t.Parallel()
+
+	set := new(descriptorpb.FileDescriptorSet)
+	require.NoError(t, prototext.Unmarshal([]byte(`file: {
+		name: "service/v1/service.proto"
+		package: "service.v1"
+		syntax: "proto3"
+		message_type: {
+			name: "Item"
+			field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+		}
+		service: {
+			name: "Service"
+			method: {
+				name: "GetItem"
+				input_type: ".service.v1.Item"
+				output_type: ".service.v1.Item"
+				options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+			}
+		}
+	}`), set))
+
+	var hooked bool
+	g, err := NewGeneratorFromFileDescriptorSet(set, []string{"service/v1/service.proto"},
+		WithSpecHook(func(spec *ogen.Spec) error {
+			hooked = true
+			spec.Info.SetTitle("Service")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.True(t, hooked)
+	require.Equal(t, "Service", g.Spec().Info.Title)
+	require.Equal(t, DefaultOpenAPI, g.Spec().OpenAPI)
+	require.Contains(t, g.Spec().Paths, "/api/v1/items/{id}")
+
+	_, err = NewGeneratorFromFileDescriptorSet(set, []string{"unknown.proto"})
+	require.ErrorContains(t, err, `find file "unknown.proto"`)
+
+	_, err = NewGeneratorFromFileDescriptorSet(set, nil, WithSpecHook(func(*ogen.Spec) error {
+		return fmt.Errorf("boom")
+	}))
+	require.ErrorContains(t, err, "spec hook: boom")
+}
+
+func TestUnmarshalFileDescriptorSet(t *testing.T) {
+	t.Parallel()
+
+	textproto, err := os.ReadFile("_testdata/crud.textproto")
+	require.NoError(t, err)
+
+	req := new(pluginpb.CodeGeneratorRequest)
+	require.NoError(t, prototext.Unmarshal(textproto, req))
+
+	set := &descriptorpb.FileDescriptorSet{File: req.GetProtoFile()}
+	binary, err := proto.Marshal(set)
+	require.NoError(t, err)
+	json, err := protojson.Marshal(set)
+	require.NoError(t, err)
+
+	for _, data := range [][]byte{binary, json} {
+		got, err := UnmarshalFileDescriptorSet(data)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(set, got))
+	}
+
+	_, err = UnmarshalFileDescriptorSet([]byte(`{"file": 1}`))
+	require.ErrorContains(t, err, "unmarshal json")
+
+	g, err := NewGeneratorFromFileDescriptorSet(set, nil, WithServiceFilter(func(s *protogen.Service) bool {
+		return false
+	}))
+	require.NoError(t, err)
+	require.Empty(t, g.Spec().Paths)
+}
@@ -0,0 +1,44 @@
+package gen
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Most of edition features are resolved by protoreflect:
+//
+//   - field_presence: LEGACY_REQUIRED fields have required cardinality,
+//     see isRequired
+//   - message_encoding: DELIMITED fields are groups, which are
+//     encoded as nested messages
+//   - enum_type: see EnumDescriptor.IsClosed
+//
+// field_presence does not change nullability: protojson omits unset fields
+// instead of writing null and accepts null for any field regardless of
+// presence, so only wrapper types are nullable.
+//
+// json_format is not exposed, so it is resolved here.
+
+// isJSONCompliant whether message uses json_format ALLOW feature.
+//
+// protoc checks JSON names of fields in such messages for conflicts, otherwise
+// (LEGACY_BEST_EFFORT, default for proto2) names may conflict.
+func isJSONCompliant(md protoreflect.MessageDescriptor) bool {
+	for d := protoreflect.Descriptor(md); d != nil; d = d.Parent() {
+		var features *descriptorpb.FeatureSet
+		switch opts := d.Options().(type) {
+		case *descriptorpb.MessageOptions:
+			features = opts.GetFeatures()
+		case *descriptorpb.FileOptions:
+			features = opts.GetFeatures()
+		}
+		switch features.GetJsonFormat() {
+		case descriptorpb.FeatureSet_ALLOW:
+			return true
+		case descriptorpb.FeatureSet_LEGACY_BEST_EFFORT:
+			return false
+		}
+	}
+	// Edition defaults.
+	return md.ParentFile().Syntax() != protoreflect.Proto2
+}
@@ -0,0 +1,257 @@
+package gen
+
+import (
+	"strings"
+
+	"github.com/go-faster/yaml"
+)
+
+// isOpenAPI30 whether target specification version is 3.0.x.
+func isOpenAPI30(version string) bool {
+	return strings.HasPrefix(version, "3.0")
+}
+
+// emitVersion rewrites encoded specification to match target OpenAPI version.
+//
+// Generator builds specification using ogen's model, which mixes
+// 3.0 (nullable) and 3.1 constructs, so the tree is fixed up before writing.
+func emitVersion(root *yaml.Node, version string) {
+	doc := root
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) == 1 {
+		doc = doc.Content[0]
+	}
+
+	rewrite := rewriteSchema31
+	if isOpenAPI30(version) {
+		rewrite = rewriteSchema30
+		downgradeDocument30(doc)
+	}
+	walkSchemas(doc, rewrite)
+}
+
+// walkSchemas calls rewrite for every Schema Object in the document.
+func walkSchemas(n *yaml.Node, rewrite func(s *yaml.Node)) {
+	if n.Kind == yaml.SequenceNode {
+		for _, elem := range n.Content {
+			walkSchemas(elem, rewrite)
+		}
+		return
+	}
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i].Value, n.Content[i+1]
+		switch key {
+		case "schema":
+			walkSchema(val, rewrite)
+		case "schemas":
+			if val.Kind == yaml.MappingNode {
+				for j := 1; j < len(val.Content); j += 2 {
+					walkSchema(val.Content[j], rewrite)
+				}
+			}
+		case "example", "examples", "default":
+			// Values, not OpenAPI objects.
+		default:
+			walkSchemas(val, rewrite)
+		}
+	}
+}
+
+func walkSchema(s *yaml.Node, rewrite func(s *yaml.Node)) {
+	if s.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(s.Content); i += 2 {
+		key, val := s.Content[i].Value, s.Content[i+1]
+		switch key {
+		case "items", "not", "additionalProperties":
+			if val.Kind == yaml.SequenceNode {
+				for _, elem := range val.Content {
+					walkSchema(elem, rewrite)
+				}
+			} else {
+				walkSchema(val, rewrite)
+			}
+		case "allOf", "oneOf", "anyOf":
+			for _, elem := range val.Content {
+				walkSchema(elem, rewrite)
+			}
+		case "properties", "patternProperties":
+			for j := 1; j < len(val.Content); j += 2 {
+				walkSchema(val.Content[j], rewrite)
+			}
+		}
+	}
+	rewrite(s)
+}
+
+// rewriteSchema31 replaces 3.0 nullable keyword with "null" type.
+func rewriteSchema31(s *yaml.Node) {
+	nullable := mappingValue(s, "nullable")
+	if nullable == nil {
+		return
+	}
+	deleteMappingKey(s, "nullable")
+	if nullable.Value != "true" {
+		return
+	}
+
+	typ := mappingValue(s, "type")
+	switch {
+	case typ == nil:
+		if ref := mappingValue(s, "$ref"); ref != nil {
+			// {$ref: X, nullable: true} => {oneOf: [{$ref: X}, {type: "null"}]}
+			deleteMappingKey(s, "$ref")
+			setMappingValue(s, "oneOf", &yaml.Node{
+				Kind: yaml.SequenceNode,
+				Tag:  "!!seq",
+				Content: []*yaml.Node{
+					mappingNode("$ref", stringNode(ref.Value)),
+					mappingNode("type", stringNode("null")),
+				},
+			})
+		}
+		return
+	case typ.Kind == yaml.ScalarNode:
+		setMappingValue(s, "type", &yaml.Node{
+			Kind:    yaml.SequenceNode,
+			Tag:     "!!seq",
+			Style:   yaml.FlowStyle,
+			Content: []*yaml.Node{stringNode(typ.Value), stringNode("null")},
+		})
+	}
+
+	if enum := mappingValue(s, "enum"); enum != nil {
+		// Enum restricts the value, so null must be listed explicitly.
+		enum.Content = append(enum.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+	}
+}
+
+// rewriteSchema30 replaces 3.1-only JSON Schema constructs with 3.0 equivalents.
+func rewriteSchema30(s *yaml.Node) {
+	// type: [T, "null"] => type: T, nullable: true
+	if typ := mappingValue(s, "type"); typ != nil && typ.Kind == yaml.SequenceNode {
+		var (
+			types    []string
+			nullable bool
+		)
+		for _, t := range typ.Content {
+			if t.Value == "null" {
+				nullable = true
+				continue
+			}
+			types = append(types, t.Value)
+		}
+
+		switch len(types) {
+		case 0:
+			deleteMappingKey(s, "type")
+		case 1:
+			setMappingValue(s, "type", stringNode(types[0]))
+		default:
+			deleteMappingKey(s, "type")
+			anyOf := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for _, t := range types {
+				anyOf.Content = append(anyOf.Content, mappingNode("type", stringNode(t)))
+			}
+			setMappingValue(s, "anyOf", anyOf)
+		}
+		if nullable {
+			setMappingValue(s, "nullable", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+	}
+
+	// const: V => enum: [V]
+	if c := mappingValue(s, "const"); c != nil {
+		deleteMappingKey(s, "const")
+		setMappingValue(s, "enum", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{c}})
+	}
+
+	// examples: [V, ...] => example: V
+	if examples := mappingValue(s, "examples"); examples != nil {
+		deleteMappingKey(s, "examples")
+		if examples.Kind == yaml.SequenceNode && len(examples.Content) > 0 && mappingValue(s, "example") == nil {
+			setMappingValue(s, "example", examples.Content[0])
+		}
+	}
+
+	// 3.0 uses "byte" format for base64-encoded strings.
+	if format := mappingValue(s, "format"); format != nil && format.Value == "base64" {
+		format.Value = "byte"
+	}
+
+	// Siblings of $ref are ignored in 3.0, wrap reference into allOf.
+	if ref := mappingValue(s, "$ref"); ref != nil && len(s.Content) > 2 {
+		deleteMappingKey(s, "$ref")
+		s.Content = append([]*yaml.Node{
+			stringNode("allOf"),
+			{
+				Kind:    yaml.SequenceNode,
+				Tag:     "!!seq",
+				Content: []*yaml.Node{mappingNode("$ref", stringNode(ref.Value))},
+			},
+		}, s.Content...)
+	}
+}
+
+// downgradeDocument30 removes document fields introduced in 3.1.
+func downgradeDocument30(doc *yaml.Node) {
+	deleteMappingKey(doc, "jsonSchemaDialect")
+	deleteMappingKey(doc, "webhooks")
+	if info := mappingValue(doc, "info"); info != nil {
+		deleteMappingKey(info, "summary")
+		if license := mappingValue(info, "license"); license != nil {
+			deleteMappingKey(license, "identifier")
+		}
+	}
+	if components := mappingValue(doc, "components"); components != nil {
+		deleteMappingKey(components, "pathItems")
+	}
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func setMappingValue(n *yaml.Node, key string, val *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content[i+1] = val
+			return
+		}
+	}
+	n.Content = append(n.Content, stringNode(key), val)
+}
+
+func deleteMappingKey(n *yaml.Node, key string) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+func mappingNode(key string, val *yaml.Node) *yaml.Node {
+	return &yaml.Node{
+		Kind:    yaml.MappingNode,
+		Tag:     "!!map",
+		Content: []*yaml.Node{stringNode(key), val},
+	}
+}
@@ -0,0 +1,311 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+)
+
+// node encodes specification to YAML node tree.
+//
+// Both YAML and JSON outputs are built from the same tree.
+func (g *Generator) node() (*yaml.Node, error) {
+	n, err := encodeNode(reflect.ValueOf(g.spec))
+	if err != nil {
+		return nil, errors.Wrap(err, "encode spec")
+	}
+	emitVersion(n, g.spec.OpenAPI)
+	if err := g.applyPatches(n); err != nil {
+		return nil, errors.Wrap(err, "apply patches")
+	}
+	return n, nil
+}
+
+var (
+	extensionsType    = reflect.TypeOf(ogen.Extensions{})
+	openAPICommonType = reflect.TypeOf(ogen.OpenAPICommon{})
+	marshalerType     = reflect.TypeOf((*yaml.Marshaler)(nil)).Elem()
+)
+
+// encodeNode encodes OpenAPI object to YAML node.
+//
+// ogen encoders do not handle inlined extensions: YAML encoder
+// drops them and JSON encoder ignores Schema extensions, so
+// objects are walked manually. Map keys are sorted in YAML encoder
+// order, so JSON output lists paths and components as YAML does.
+func encodeNode(v reflect.Value) (*yaml.Node, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		v = v.Elem()
+	}
+
+	switch val := v.Interface().(type) {
+	case ogen.Properties:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, prop := range val {
+			if err := appendField(n, prop.Name, reflect.ValueOf(prop.Schema)); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	case ogen.PatternProperties:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, prop := range val {
+			if err := appendField(n, prop.Pattern, reflect.ValueOf(prop.Schema)); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	case ogen.Items:
+		if val.Item != nil {
+			return encodeNode(reflect.ValueOf(val.Item))
+		}
+		return encodeNode(reflect.ValueOf(val.Items))
+	case ogen.AdditionalProperties:
+		if val.Bool != nil {
+			return encodeNode(reflect.ValueOf(val.Bool))
+		}
+		return encodeNode(reflect.ValueOf(val.Schema))
+	}
+
+	if v.Type().Implements(marshalerType) {
+		// Leaf values like enum, default and example.
+		var n yaml.Node
+		if err := n.Encode(v.Interface()); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if err := appendStructFields(n, v); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case reflect.Map:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys, err := sortedKeys(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if err := appendField(n, key, v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))); err != nil {
+				return nil, err
+			}
+		}
+		return n, nil
+	case reflect.Slice, reflect.Array:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i := 0; i < v.Len(); i++ {
+			elem, err := encodeNode(v.Index(i))
+			if err != nil {
+				return nil, errors.Wrapf(err, "[%d]", i)
+			}
+			n.Content = append(n.Content, elem)
+		}
+		return n, nil
+	default:
+		var n yaml.Node
+		if err := n.Encode(v.Interface()); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+}
+
+func appendStructFields(n *yaml.Node, v reflect.Value) error {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+
+		fv := v.Field(i)
+		switch {
+		case field.Type == openAPICommonType:
+			appendExtensions(n, fv.Field(0).Interface().(ogen.Extensions))
+			continue
+		case field.Type == extensionsType:
+			appendExtensions(n, fv.Interface().(ogen.Extensions))
+			continue
+		case flags == "inline":
+			if err := appendStructFields(n, fv); err != nil {
+				return err
+			}
+			continue
+		case flags == "omitempty" && isEmptyValue(fv):
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if err := appendField(n, name, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendExtensions(n *yaml.Node, ext ogen.Extensions) {
+	keys := maps.Keys(ext)
+	slices.Sort(keys)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "x-") {
+			continue
+		}
+		val := ext[key]
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&val,
+		)
+	}
+}
+
+func appendField(n *yaml.Node, key string, v reflect.Value) error {
+	val, err := encodeNode(v)
+	if err != nil {
+		return errors.Wrapf(err, "%q", key)
+	}
+	n.Content = append(n.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		val,
+	)
+	return nil
+}
+
+// sortedKeys returns map keys in YAML encoder order.
+func sortedKeys(v reflect.Value) ([]string, error) {
+	keys := make(map[string]struct{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		keys[iter.Key().String()] = struct{}{}
+	}
+
+	// Let the YAML encoder sort the keys to keep its natural order.
+	var n yaml.Node
+	if err := n.Encode(keys); err != nil {
+		return nil, err
+	}
+
+	r := make([]string, 0, len(keys))
+	for i := 0; i < len(n.Content); i += 2 {
+		r = append(r, n.Content[i].Value)
+	}
+	return r, nil
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// writeJSON writes YAML node as compact JSON.
+func writeJSON(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) != 1 {
+			return errors.Errorf("unexpected document length %d", len(n.Content))
+		}
+		return writeJSON(buf, n.Content[0])
+	case yaml.AliasNode:
+		return writeJSON(buf, n.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(n.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSON(buf, n.Content[i+1]); err != nil {
+				return errors.Wrapf(err, "key %q", n.Content[i].Value)
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, elem := range n.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, elem); err != nil {
+				return errors.Wrapf(err, "index %d", i)
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		return writeJSONScalar(buf, n)
+	default:
+		return errors.Errorf("unexpected node kind %v", n.Kind)
+	}
+}
+
+func writeJSONScalar(buf *bytes.Buffer, n *yaml.Node) error {
+	switch n.ShortTag() {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		var v bool
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(v))
+		return nil
+	case "!!int", "!!float":
+		var v float64
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return errors.Errorf("cannot represent %q in JSON", n.Value)
+		}
+		if json.Valid([]byte(n.Value)) {
+			// Keep original representation to not lose precision.
+			buf.WriteString(n.Value)
+			return nil
+		}
+		buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		return nil
+	default:
+		data, err := json.Marshal(n.Value)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package gen
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-faster/yaml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ogen-go/ogen"
+)
+
+func encodeTestJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	n, err := encodeNode(reflect.ValueOf(v))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, n))
+	return buf.String()
+}
+
+func Test_encodeNode(t *testing.T) {
+	t.Parallel()
+
+	s := ogen.NewSchema().
+		SetType("object").
+		AddRequiredProperties(ogen.String().ToProperty("name")).
+		AddOptionalProperties(ogen.Int64().ToProperty("count"))
+	setExtension(&s.Common.Extensions, "x-resource-type", "example.com/Item")
+
+	spec := ogen.NewSpec()
+	spec.Init()
+	spec.SetOpenAPI("3.1.0")
+	spec.AddSchema("Item", s)
+	spec.AddSchema("Book", ogen.String())
+	spec.AddPathItem("/api/v1/{id}", ogen.NewPathItem())
+	spec.AddPathItem("/api/v1/single_field/{id}", ogen.NewPathItem())
+	spec.Extensions = ogen.Extensions{}
+	setExtension(&spec.Extensions, "x-ogen", true)
+
+	for _, tt := range []struct {
+		name  string
+		input any
+		want  string
+	}{
+		{
+			// ogen encoders drop extensions of Schema.
+			"SchemaExtensions",
+			s,
+			`{"type":"object","properties":{"name":{"type":"string"},"count":{"type":"integer","format":"int64"}},"required":["name"],"x-resource-type":"example.com/Item"}`,
+		},
+		{
+			// Map keys are sorted in YAML encoder order for both outputs.
+			"Spec",
+			spec,
+			`{"openapi":"3.1.0","info":{"title":"","version":""},"paths":{"/api/v1/{id}":{},"/api/v1/single_field/{id}":{}},"components":{"schemas":{"Book":{"type":"string"},"Item":{"type":"object","properties":{"name":{"type":"string"},"count":{"type":"integer","format":"int64"}},"required":["name"],"x-resource-type":"example.com/Item"}}},"x-ogen":true}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, encodeTestJSON(t, tt.input))
+		})
+	}
+}
+
+func Test_writeJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		input   string
+		want    string
+		wantErr string
+	}{
+		{`{b: 1, a: [true, null]}`, `{"b":1,"a":[true,null]}`, ""},
+		// Numbers keep original representation.
+		{`9007199254740993`, `9007199254740993`, ""},
+		{`1e3`, `1e3`, ""},
+		{`0x10`, `16`, ""},
+		{`"42"`, `"42"`, ""},
+		{`.inf`, "", `cannot represent ".inf" in JSON`},
+	} {
+		var n yaml.Node
+		require.NoError(t, yaml.Unmarshal([]byte(tt.input), &n))
+
+		var buf bytes.Buffer
+		err := writeJSON(&buf, &n)
+		if tt.wantErr != "" {
+			require.EqualError(t, err, tt.wantErr, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.want, buf.String(), tt.input)
+	}
+}
@@ -0,0 +1,454 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	pathpkg "path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+
+	"github.com/ogen-go/protoc-gen-oas/oas"
+)
+
+// exampleMarker starts example block in comment, the rest of the comment is example.
+const exampleMarker = "Example:"
+
+// exampleExtensions are supported example file extensions.
+var exampleExtensions = []string{".json", ".textproto", ".txtpb"}
+
+// pendingExample is an example validated after all schemas are generated.
+type pendingExample struct {
+	what   string
+	schema *ogen.Schema
+	value  *yaml.Node
+}
+
+// splitExample splits comment into description and example block.
+//
+// Example block which is not valid JSON is kept in description and returned as error,
+// so prose like "Example: shelves/1" does not fail generation.
+func splitExample(comment string) (description, example string, err error) {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		text := strings.TrimLeft(line, "/ \t")
+		if !strings.HasPrefix(text, exampleMarker) {
+			continue
+		}
+
+		rest := []string{strings.TrimPrefix(text, exampleMarker)}
+		for _, line := range lines[i+1:] {
+			rest = append(rest, strings.TrimPrefix(line, "//"))
+		}
+		example = strings.TrimSpace(strings.Join(rest, "\n"))
+		if !json.Valid([]byte(example)) {
+			return comment, "", errors.Errorf("%s block %q is not valid JSON, kept as description", exampleMarker, example)
+		}
+		return strings.Join(lines[:i], "\n"), example, nil
+	}
+	return comment, "", nil
+}
+
+// exampleIssue is an issue of comment example found by generator.
+type exampleIssue struct {
+	ptr     string
+	message string
+}
+
+// addExampleIssue records issue of comment example of object at JSON Pointer.
+func (g *Generator) addExampleIssue(ptr string, err error) {
+	if slices.ContainsFunc(g.exampleIssues, func(issue exampleIssue) bool { return issue.ptr == ptr }) {
+		// Field schema may be generated more than once, e.g. for parameters.
+		return
+	}
+	g.exampleIssues = append(g.exampleIssues, exampleIssue{ptr: ptr, message: err.Error()})
+}
+
+// mkMessageExample returns example of message from (oas.schema) option,
+// leading comment or example file, in order of precedence.
+func (g *Generator) mkMessageExample(msg *protogen.Message) (ogen.ExampleValue, error) {
+	md := msg.Desc
+
+	if opt, ok := proto.GetExtension(md.Options(), oas.E_Schema).(*oas.Schema); ok && opt.GetExample() != "" {
+		v, err := g.convertMessageExample(md, []byte(opt.GetExample()), ".json")
+		if err != nil {
+			return nil, errors.Wrap(err, "oas.schema example")
+		}
+		return v, nil
+	}
+
+	_, example, err := splitExample(string(msg.Comments.Leading))
+	if err != nil {
+		g.addExampleIssue(jsonPointer("components", "schemas", descriptorName(md)), err)
+	}
+	if example != "" {
+		v, err := g.convertMessageExample(md, []byte(example), ".json")
+		if err != nil {
+			return nil, errors.Wrap(err, "comment example")
+		}
+		return v, nil
+	}
+
+	if g.exampleFS == nil {
+		return nil, nil
+	}
+	for _, ext := range exampleExtensions {
+		name := string(md.FullName()) + ext
+		data, err := fs.ReadFile(g.exampleFS, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := g.convertMessageExample(md, data, ext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "example file %q", name)
+		}
+		return v, nil
+	}
+	return nil, nil
+}
+
+// mkMediaExamples returns named examples of message read from
+// <full name>/<example name>.<ext> example files.
+func (g *Generator) mkMediaExamples(md protoreflect.MessageDescriptor) (map[string]*ogen.Example, error) {
+	if g.exampleFS == nil {
+		return nil, nil
+	}
+
+	dir := string(md.FullName())
+	entries, err := fs.ReadDir(g.exampleFS, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	examples := make(map[string]*ogen.Example)
+	for _, e := range entries {
+		ext := pathpkg.Ext(e.Name())
+		if e.IsDir() || !slices.Contains(exampleExtensions, ext) {
+			continue
+		}
+
+		name := dir + "/" + e.Name()
+		data, err := fs.ReadFile(g.exampleFS, name)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := g.convertMessageExample(md, data, ext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "example file %q", name)
+		}
+		examples[strings.TrimSuffix(e.Name(), ext)] = &ogen.Example{Value: v}
+	}
+	if len(examples) == 0 {
+		return nil, nil
+	}
+	return examples, nil
+}
+
+// setMediaExamples sets named examples of message to JSON content of media.
+func (g *Generator) setMediaExamples(content map[string]ogen.Media, md protoreflect.MessageDescriptor) error {
+	examples, err := g.mkMediaExamples(md)
+	if err != nil || examples == nil {
+		return err
+	}
+
+	media := content["application/json"]
+	media.Examples = examples
+	content["application/json"] = media
+
+	for name, e := range examples {
+		if err := g.addExample(string(md.FullName())+" example "+name, media.Schema, e.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mkFieldExample returns example of field from (oas.field) option or comment.
+func (g *Generator) mkFieldExample(fd protoreflect.FieldDescriptor, comment string) (ogen.ExampleValue, error) {
+	example := ""
+	if opt, ok := proto.GetExtension(fd.Options(), oas.E_Field).(*oas.Field); ok && opt.GetExample() != "" {
+		example = opt.GetExample()
+	} else {
+		var err error
+		if _, example, err = splitExample(comment); err != nil {
+			ptr := jsonPointer("components", "schemas", descriptorName(fd.ContainingMessage()))
+			g.addExampleIssue(ptr+"/properties/"+escapePointer(fieldJSONName(fd)), err)
+		}
+		if example == "" {
+			return nil, nil
+		}
+	}
+
+	if !json.Valid([]byte(example)) {
+		return nil, errors.Errorf("example %q is not valid JSON", example)
+	}
+
+	// Parse field as a part of containing message to use protojson rules.
+	md := fd.ContainingMessage()
+	msg := dynamicpb.NewMessage(md)
+	data := []byte(`{"` + fd.JSONName() + `":` + example + `}`)
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return nil, errors.Wrap(err, "parse example")
+	}
+
+	data, err := protojson.MarshalOptions{EmitDefaultValues: true}.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode example")
+	}
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, errors.Wrap(err, "decode example")
+	}
+
+	v := mappingValue(n.Content[0], fd.JSONName())
+	if v == nil {
+		return nil, errors.New("example is empty")
+	}
+	normalizeExampleField(fd, v)
+
+	return encodeExample(v)
+}
+
+// convertMessageExample parses message example in JSON or text format
+// and encodes it as protojson does.
+func (g *Generator) convertMessageExample(md protoreflect.MessageDescriptor, data []byte, ext string) (ogen.ExampleValue, error) {
+	msg := dynamicpb.NewMessage(md)
+
+	var err error
+	if ext == ".json" {
+		err = protojson.Unmarshal(data, msg)
+	} else {
+		err = prototext.Unmarshal(data, msg)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "parse example")
+	}
+
+	// Use the same options as field examples, so zero values are shown.
+	data, err = protojson.MarshalOptions{EmitDefaultValues: true}.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "encode example")
+	}
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return nil, errors.Wrap(err, "decode example")
+	}
+	if len(n.Content) == 0 {
+		// Empty message.
+		return ogen.ExampleValue(`{}`), nil
+	}
+	normalizeExampleMessage(md, n.Content[0])
+
+	return encodeExample(n.Content[0])
+}
+
+func encodeExample(n *yaml.Node) (ogen.ExampleValue, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, n); err != nil {
+		return nil, errors.Wrap(err, "encode example")
+	}
+	return buf.Bytes(), nil
+}
+
+// normalizeExampleMessage converts 64-bit integers, which protojson
+// encodes as strings, to numbers to match generated schema.
+func normalizeExampleMessage(md protoreflect.MessageDescriptor, n *yaml.Node) {
+	switch md.FullName() {
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		normalizeInt64(n)
+		return
+	}
+	if n.Kind != yaml.MappingNode {
+		// Well-known types with special JSON mapping.
+		return
+	}
+
+	fields := md.Fields()
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fd := fields.ByJSONName(n.Content[i].Value)
+		if fd == nil {
+			continue
+		}
+		normalizeExampleField(fd, n.Content[i+1])
+	}
+}
+
+func normalizeExampleField(fd protoreflect.FieldDescriptor, n *yaml.Node) {
+	switch {
+	case fd.IsMap():
+		if n.Kind == yaml.MappingNode {
+			for i := 1; i < len(n.Content); i += 2 {
+				normalizeExampleValue(fd.MapValue(), n.Content[i])
+			}
+		}
+	case fd.IsList():
+		if n.Kind == yaml.SequenceNode {
+			for _, elem := range n.Content {
+				normalizeExampleValue(fd, elem)
+			}
+		}
+	default:
+		normalizeExampleValue(fd, n)
+	}
+}
+
+func normalizeExampleValue(fd protoreflect.FieldDescriptor, n *yaml.Node) {
+	switch fd.Kind() {
+	case protoreflect.Int64Kind,
+		protoreflect.Sint64Kind,
+		protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind,
+		protoreflect.Fixed64Kind:
+		normalizeInt64(n)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		normalizeExampleMessage(fd.Message(), n)
+	}
+}
+
+func normalizeInt64(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" {
+		n.Tag = "!!int"
+		n.Style = 0
+	}
+}
+
+// addExample schedules validation of example against schema.
+func (g *Generator) addExample(what string, s *ogen.Schema, v ogen.ExampleValue) error {
+	var n yaml.Node
+	if err := yaml.Unmarshal(v, &n); err != nil {
+		return errors.Wrapf(err, "decode example of %s", what)
+	}
+	if len(n.Content) == 0 {
+		return errors.Errorf("decode example of %s: empty document", what)
+	}
+	g.examples = append(g.examples, pendingExample{what: what, schema: s, value: n.Content[0]})
+	return nil
+}
+
+// validateExamples validates examples against generated schemas.
+func (g *Generator) validateExamples() error {
+	for _, e := range g.examples {
+		if err := g.validateExample(e.schema, e.value, "$"); err != nil {
+			return errors.Wrapf(err, "invalid example of %s", e.what)
+		}
+	}
+	return nil
+}
+
+func (g *Generator) validateExample(s *ogen.Schema, n *yaml.Node, at string) error {
+	if s == nil {
+		return nil
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, schemaRef(""))
+		ref, ok := g.spec.Components.Schemas[name]
+		if !ok {
+			return errors.Errorf("%s: unknown schema %q", at, s.Ref)
+		}
+		return g.validateExample(ref, n, at)
+	}
+
+	if n.Tag == "!!null" {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return errors.Errorf("%s: null is not allowed", at)
+	}
+
+	if len(s.Enum) > 0 {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, n); err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(s.Enum, func(v json.RawMessage) bool {
+			return bytes.Equal(v, buf.Bytes())
+		}) {
+			return errors.Errorf("%s: value %s is not one of enum values", at, buf.String())
+		}
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		if n.Kind != yaml.MappingNode {
+			return errors.Errorf("%s: expected object", at)
+		}
+		variantProps, err := g.unionProperties(s, n, at)
+		if err != nil {
+			return err
+		}
+		props := append(slices.Clone(s.Properties), variantProps...)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i].Value, n.Content[i+1]
+			keyAt := at + "." + key
+
+			idx := slices.IndexFunc(props, func(p ogen.Property) bool { return p.Name == key })
+			switch {
+			case idx >= 0:
+				if err := g.validateExample(props[idx].Schema, val, keyAt); err != nil {
+					return err
+				}
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Bool == nil:
+				if err := g.validateExample(&s.AdditionalProperties.Schema, val, keyAt); err != nil {
+					return err
+				}
+			default:
+				return errors.Errorf("%s: unknown property", keyAt)
+			}
+		}
+		for _, req := range s.Required {
+			if mappingValue(n, req) == nil {
+				return errors.Errorf("%s: required property %q is missing", at, req)
+			}
+		}
+	case "array":
+		if n.Kind != yaml.SequenceNode {
+			return errors.Errorf("%s: expected array", at)
+		}
+		if s.Items != nil && s.Items.Item != nil {
+			for i, elem := range n.Content {
+				if err := g.validateExample(s.Items.Item, elem, at+"["+strconv.Itoa(i)+"]"); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if n.Tag != "!!str" {
+			return errors.Errorf("%s: expected string", at)
+		}
+	case "integer":
+		if n.Tag != "!!int" {
+			return errors.Errorf("%s: expected integer", at)
+		}
+	case "number":
+		if n.Tag != "!!int" && n.Tag != "!!float" {
+			return errors.Errorf("%s: expected number", at)
+		}
+	case "boolean":
+		if n.Tag != "!!bool" {
+			return errors.Errorf("%s: expected boolean", at)
+		}
+	}
+	return nil
+}
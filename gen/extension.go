@@ -0,0 +1,23 @@
+package gen
+
+import (
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+)
+
+// setExtension sets OpenAPI extension field.
+//
+// Value must be encodable as YAML.
+func setExtension(ext *ogen.Extensions, key string, value any) {
+	var n yaml.Node
+	if err := n.Encode(value); err != nil {
+		// Caller passes plain values, so encoding never fails.
+		panic(err)
+	}
+
+	if *ext == nil {
+		*ext = ogen.Extensions{}
+	}
+	(*ext)[key] = n
+}
@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/ogen-go/ogen"
+)
+
+// fieldMaskDescription documents protojson encoding of google.protobuf.FieldMask.
+const fieldMaskDescription = "Comma-separated list of field paths in lowerCamelCase, e.g. `title,author.displayName`."
+
+// fieldMaskPattern matches comma-separated field paths.
+const fieldMaskPattern = `^([A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*(,[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*)*)?$`
+
+func isFieldMask(md protoreflect.MessageDescriptor) bool {
+	return md.FullName() == "google.protobuf.FieldMask"
+}
+
+// setFieldMaskPaths lists valid paths of the resource message FieldMask field
+// refers to in x-field-mask-paths, if enabled.
+func (g *Generator) setFieldMaskPaths(s *ogen.Schema, fd protoreflect.FieldDescriptor) {
+	if !g.fieldMaskPaths {
+		return
+	}
+	target := fieldMaskTarget(fd)
+	if target == nil {
+		return
+	}
+	if paths := g.fieldMaskPathsOf(target, "", map[protoreflect.FullName]bool{}); len(paths) > 0 {
+		setExtension(&s.Common.Extensions, "x-field-mask-paths", paths)
+	}
+}
+
+// fieldMaskTarget returns resource message FieldMask field refers to.
+//
+// The resource is the only singular message field of the containing message,
+// as in AIP-134 update requests.
+func fieldMaskTarget(fd protoreflect.FieldDescriptor) (target protoreflect.MessageDescriptor) {
+	fields := fd.ContainingMessage().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if f.Kind() != protoreflect.MessageKind || f.IsList() || f.IsMap() {
+			continue
+		}
+		md := f.Message()
+		if md.FullName().Parent() == "google.protobuf" {
+			continue
+		}
+		if target != nil {
+			// Ambiguous.
+			return nil
+		}
+		target = md
+	}
+	return target
+}
+
+// fieldMaskPathsOf returns camelCase paths of message fields, including nested ones.
+func (g *Generator) fieldMaskPathsOf(md protoreflect.MessageDescriptor, prefix string, seen map[protoreflect.FullName]bool) (paths []string) {
+	if seen[md.FullName()] {
+		return nil
+	}
+	seen[md.FullName()] = true
+	defer delete(seen, md.FullName())
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		f := fields.Get(i)
+		if g.isHiddenFieldDesc(f) {
+			continue
+		}
+
+		path := prefix + f.JSONName()
+		paths = append(paths, path)
+
+		if f.Message() == nil || f.IsList() || f.IsMap() {
+			continue
+		}
+		if f.Message().FullName().Parent() == "google.protobuf" {
+			// Well-known types are set as a whole.
+			continue
+		}
+		paths = append(paths, g.fieldMaskPathsOf(f.Message(), path+".", seen)...)
+	}
+	return paths
+}
@@ -0,0 +1,84 @@
+package gen
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/go-faster/errors"
+)
+
+// fieldPath is a resolved transcoding FieldPath, e.g. "book.name".
+type fieldPath []*protogen.Field
+
+// Leaf returns the last field of the path.
+func (p fieldPath) Leaf() *protogen.Field {
+	return p[len(p)-1]
+}
+
+// JSONName returns dot-separated JSON names of path fields.
+func (p fieldPath) JSONName() string {
+	names := make([]string, len(p))
+	for i, f := range p {
+		names[i] = f.Desc.JSONName()
+	}
+	return strings.Join(names, ".")
+}
+
+// resolveFieldPath resolves dot-separated proto field names starting from given message.
+//
+// Every field except the last one must be a singular message.
+func resolveFieldPath(msg *protogen.Message, path string) (fieldPath, error) {
+	var (
+		names = strings.Split(path, ".")
+		r     = make(fieldPath, 0, len(names))
+	)
+	for i, name := range names {
+		if msg == nil {
+			return nil, errors.Errorf("field %q is not a message", strings.Join(names[:i], "."))
+		}
+
+		var field *protogen.Field
+		for _, f := range msg.Fields {
+			if string(f.Desc.Name()) == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return nil, errors.Errorf("unknown field %q", strings.Join(names[:i+1], "."))
+		}
+		if i < len(names)-1 && (field.Desc.IsList() || field.Desc.IsMap()) {
+			return nil, errors.Errorf("field %q is not a singular message", strings.Join(names[:i+1], "."))
+		}
+
+		r = append(r, field)
+		msg = field.Message
+	}
+	return r, nil
+}
+
+// boundFields is a set of proto field paths mapped to path parameters or body.
+type boundFields map[string]struct{}
+
+// Add adds path to the set.
+func (b boundFields) Add(path string) {
+	b[path] = struct{}{}
+}
+
+// Has whether path is bound.
+func (b boundFields) Has(path string) bool {
+	_, ok := b[path]
+	return ok
+}
+
+// HasNested whether some field nested in path is bound.
+func (b boundFields) HasNested(path string) bool {
+	prefix := path + "."
+	for p := range b {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,6 @@
+// Package gen for generate OpenAPI specification from proto.
+//
+// Generator can be created from protoc plugin files (NewGenerator) or from
+// resolved descriptors (NewGeneratorFromDescriptors), generated
+// specification can be post-processed with WithSpecHook.
+package gen
@@ -0,0 +1,778 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+)
+
+// NewGenerator returns new Generator instance.
+func NewGenerator(files []*protogen.File, opts ...GeneratorOption) (*Generator, error) {
+	g := new(Generator)
+	g.init()
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.collectResources(files)
+	g.collectMessages(files)
+	g.collectExtensions(files)
+
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+
+		for _, e := range f.Enums {
+			g.mkEnum(e)
+		}
+
+		for _, s := range f.Services {
+			if !g.filterService(s) {
+				continue
+			}
+
+			for _, m := range s.Methods {
+				ok, err := g.checkStreaming(m)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+
+				for _, rule := range collectRules(m.Desc.Options()) {
+					isDeprecated := isDeprecatedMethod(m.Desc.Options())
+					tmpl, op, err := g.mkMethod(rule, m, isDeprecated)
+					if err != nil {
+						return nil, errors.Wrapf(err, "make method %s => %s %s mapping", m.Desc.FullName(), rule.Method, rule.Path)
+					}
+
+					pi := g.spec.Paths[tmpl]
+					if pi == nil {
+						pi = ogen.NewPathItem()
+						g.spec.AddPathItem(tmpl, pi)
+					}
+
+					var to **ogen.Operation
+					switch rule.Method {
+					case http.MethodGet:
+						to = &pi.Get
+					case http.MethodPut:
+						to = &pi.Put
+					case http.MethodPost:
+						to = &pi.Post
+					case http.MethodDelete:
+						to = &pi.Delete
+					case http.MethodPatch:
+						to = &pi.Patch
+					}
+
+					if *to != nil {
+						return nil, errors.Errorf("conflict on endpoint %s %s", rule.Method, tmpl)
+					}
+					*to = op
+					g.setSource(jsonPointer("paths", tmpl, strings.ToLower(rule.Method)), f.Desc, m.Location)
+				}
+			}
+		}
+	}
+
+	if g.operationsPrefix != "" {
+		if err := g.mkOperationsPaths(); err != nil {
+			return nil, errors.Wrap(err, "make operations paths")
+		}
+	}
+
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+
+		for _, m := range f.Messages {
+			name := descriptorName(m.Desc)
+
+			if owner, ok := g.generated[name]; ok && g.hasRef(name) {
+				return nil, errors.Errorf("schema %q conflicts with generated schema of %s", name, owner)
+			}
+
+			if ok := g.hasSchema(name); ok {
+				continue
+			}
+
+			if ok := g.hasRequest(name); ok {
+				continue
+			}
+
+			if !g.hasRef(name) {
+				continue
+			}
+
+			if err := g.mkSchema(m); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := g.constrainPagedRequests(); err != nil {
+		return nil, errors.Wrap(err, "constrain page size")
+	}
+
+	if err := g.shareParameters(); err != nil {
+		return nil, errors.Wrap(err, "share parameters")
+	}
+
+	if err := g.mkInfoDefaults(files); err != nil {
+		return nil, err
+	}
+
+	if err := g.validateExamples(); err != nil {
+		return nil, err
+	}
+
+	if err := g.mergeBase(); err != nil {
+		return nil, errors.Wrap(err, "merge base")
+	}
+
+	for _, hook := range g.hooks {
+		if err := hook(g.spec); err != nil {
+			return nil, errors.Wrap(err, "spec hook")
+		}
+	}
+
+	if len(g.patches) > 0 {
+		// Patches are applied on output, report their errors early.
+		if _, err := g.node(); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Generator instance.
+type Generator struct {
+	spec              *ogen.Spec
+	indent            int
+	fieldOrder        FieldOrder
+	streamContentType string
+	streamingPolicy   StreamingPolicy
+
+	queryRecursionLimit int
+	hooks               []SpecHook
+	serviceFilters      []func(s *protogen.Service) bool
+	serviceOverrides    map[protoreflect.FullName]ServiceOverride
+	operationIDNaming   OperationIDNaming
+	visibilityLabels    []string
+	base                []byte
+	basePolicy          MergePolicy
+	patches             []patch
+	exampleFS           fs.FS
+	examples            []pendingExample
+	sources             map[string]source
+	fieldMaskPaths      bool
+	maxPageSize         int
+	pagedRequests       map[string]*pagedRequest
+	pathIssues          []pathIssue
+	exampleIssues       []exampleIssue
+	resources           map[string]*annotations.ResourceDescriptor
+	messages            map[protoreflect.FullName]*protogen.Message
+	extensions          map[protoreflect.FullName][]*protogen.Extension
+	operations          map[string]operationInfo
+	operationsPrefix    string
+	generated           map[string]string
+	streamResults       map[string]string
+	sharedParameters    int
+	requests            map[string]struct{}
+	descriptorNames     map[string]struct{}
+	refs                map[string]struct{}
+}
+
+// Spec returns generated OpenAPI specification.
+//
+// Changes made to the returned value are reflected in YAML and JSON output,
+// patches are applied to the output and are not reflected in the returned value.
+func (g *Generator) Spec() *ogen.Spec {
+	return g.spec
+}
+
+// YAML returns OpenAPI specification bytes.
+func (g *Generator) YAML() ([]byte, error) {
+	n, err := g.node()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(g.indent)
+
+	if err := enc.Encode(n); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// JSON returns OpenAPI specification bytes.
+func (g *Generator) JSON() ([]byte, error) {
+	n, err := g.node()
+	if err != nil {
+		return nil, err
+	}
+
+	var compact bytes.Buffer
+	if err := writeJSON(&compact, n); err != nil {
+		return nil, errors.Wrap(err, "encode json")
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact.Bytes(), "", strings.Repeat(" ", g.indent)); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+func (g *Generator) init() {
+	g.spec = ogen.NewSpec()
+	g.spec.Init()
+	g.spec.SetOpenAPI(DefaultOpenAPI)
+	g.indent = 2
+	g.fieldOrder = FieldOrderDeclaration
+	g.streamContentType = ContentTypeNDJSON
+	g.streamingPolicy = StreamingPolicyError
+	g.operationIDNaming = OperationIDNamingMethod
+	g.serviceOverrides = make(map[protoreflect.FullName]ServiceOverride)
+	g.requests = make(map[string]struct{})
+	g.descriptorNames = make(map[string]struct{})
+	g.refs = make(map[string]struct{})
+	g.sources = make(map[string]source)
+	g.maxPageSize = DefaultMaxPageSize
+	g.pagedRequests = make(map[string]*pagedRequest)
+	g.resources = make(map[string]*annotations.ResourceDescriptor)
+	g.messages = make(map[protoreflect.FullName]*protogen.Message)
+	g.extensions = make(map[protoreflect.FullName][]*protogen.Extension)
+	g.operations = make(map[string]operationInfo)
+	g.generated = make(map[string]string)
+	g.streamResults = make(map[string]string)
+}
+
+func (g *Generator) filterService(s *protogen.Service) bool {
+	for _, filter := range g.serviceFilters {
+		if !filter(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Generator) operationID(m *protogen.Method) string {
+	if g.operationIDNaming == OperationIDNamingServiceMethod {
+		return LowerCamelCase(m.Parent.Desc.Name()) + CamelCase(m.Desc.Name())
+	}
+	return LowerCamelCase(m.Desc.Name())
+}
+
+func (g *Generator) mkMethod(rule HTTPRule, m *protogen.Method, deprecated bool) (string, *ogen.Operation, error) {
+	op := ogen.NewOperation()
+	if !rule.Additional {
+		op.SetOperationID(g.operationID(m))
+	}
+	op.SetDescription(mkCommentText(string(m.Comments.Leading)))
+	op.Deprecated = deprecated
+
+	if override, ok := g.serviceOverrides[m.Parent.Desc.FullName()]; ok {
+		op.Tags = append(op.Tags, override.Tags...)
+		op.Security = override.Security
+	}
+
+	tmpl, err := g.mkInput(rule, m, op)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "make input")
+	}
+
+	if err := g.mkOutput(rule, m, op); err != nil {
+		return "", nil, errors.Wrap(err, "make output")
+	}
+	g.setPagination(op, m)
+
+	if err := g.mkHeaders(m, op); err != nil {
+		return "", nil, errors.Wrap(err, "make headers")
+	}
+
+	return tmpl, op, nil
+}
+
+func (g *Generator) mkInput(rule HTTPRule, m *protogen.Method, op *ogen.Operation) (string, error) {
+	name := descriptorName(m.Input.Desc)
+	g.setRequest(name)
+
+	var (
+		bound         = boundFields{}
+		hasPathParams bool
+		mismatches    []string
+	)
+
+	pathTmpl, err := parsePathTemplate(rule.Path)
+	if err != nil {
+		return "", errors.Wrap(err, "parse path template")
+	}
+
+	var tmpl strings.Builder
+	tmpl.WriteByte('/')
+	for _, part := range pathTmpl.Path {
+		if !part.IsParam() {
+			tmpl.WriteString(part.Raw)
+			continue
+		}
+		hasPathParams = true
+
+		name := part.Param
+		path, err := resolveFieldPath(m.Input, name)
+		if err != nil {
+			return "", errors.Wrapf(err, "resolve path parameter %q", name)
+		}
+
+		specName := path.JSONName()
+		fd := path.Leaf().Desc
+		if part.Template == "" {
+			// Variable without template is a single segment, as {name=*}.
+			p, err := g.mkParameter("path", specName, path.Leaf())
+			if err != nil {
+				return "", err
+			}
+			if patterns := g.resourcePatterns(fd); len(patterns) > 0 {
+				// Resource name pattern of field does not apply to the segment.
+				p.Schema.SetPattern(templateRegex("*"))
+				if !matchPatterns("*", patterns) {
+					mismatches = append(mismatches, templateMismatch(name, "*", patterns))
+				}
+			}
+			tmpl.WriteString("{" + specName + "}")
+			if err := addPathParameter(op, p); err != nil {
+				return "", err
+			}
+			bound.Add(name)
+			continue
+		}
+
+		// Literal segments of template are part of path, wildcards are parameters.
+		if fd.Kind() != protoreflect.StringKind || fd.IsList() {
+			return "", errors.Errorf("path parameter %q: segments template %q requires string field", name, part.Template)
+		}
+		expanded, vars, ok := g.expandPathTemplate(fd, specName, part.Template)
+		if !ok {
+			mismatches = append(mismatches, templateMismatch(name, part.Template, g.resourcePatterns(fd)))
+		}
+		tmpl.WriteString(expanded)
+		for _, v := range vars {
+			p := ogen.NewParameter().
+				SetIn("path").
+				SetName(v.name).
+				SetDescription(fmt.Sprintf("Segment of %s %q.", specName, expanded)).
+				SetRequired(true).
+				SetSchema(ogen.NewSchema().SetType("string").SetPattern(templateRegex(v.template)))
+			if err := addPathParameter(op, p); err != nil {
+				return "", err
+			}
+		}
+		bound.Add(name)
+	}
+	for _, msg := range mismatches {
+		g.pathIssues = append(g.pathIssues, pathIssue{path: tmpl.String(), method: rule.Method, message: msg})
+	}
+
+	var (
+		s        *ogen.Schema
+		required bool
+	)
+	switch body := rule.Body; {
+	case body == "*":
+		// All remaining fields are inside request body.
+		required = true
+
+		s = ogen.NewSchema()
+		switch {
+		case !hasPathParams:
+			// Special case: all message fields are inside body, generate a direct reference to schema.
+			if err := g.mkSchema(m.Input); err != nil {
+				return "", errors.Wrap(err, "make schema for input")
+			}
+			s.SetRef(descriptorRef(m.Input.Desc))
+		default:
+			// Map remaining fields.
+			if err := g.mkPartialJSONFields(s, m.Input.Fields, "", bound); err != nil {
+				return "", errors.Wrap(err, "make requestBody schema")
+			}
+
+			if len(s.Properties) == 0 {
+				// Special case: no remaining fields.
+				s = nil
+			}
+		}
+	case body != "":
+		// This field is body, remaining fields are query parameters.
+		path, err := resolveFieldPath(m.Input, body)
+		if err != nil {
+			return "", errors.Wrap(err, "resolve body")
+		}
+		f := path.Leaf()
+		required = isRequired(f.Desc)
+
+		if ref, ok, err := g.mkRequestBodyComponent(f, required); err != nil {
+			return "", errors.Wrapf(err, "make requestBody component (field: %q)", body)
+		} else if ok {
+			op.SetRequestBody(ogen.NewRequestBody().SetRef(ref))
+		} else {
+			fieldSch, err := g.mkFieldSchema(f.Desc, f.Comments.Trailing.String())
+			if err != nil {
+				return "", errors.Wrapf(err, "make requestBody schema (field: %q)", body)
+			}
+			s = fieldSch
+		}
+
+		bound.Add(body)
+		fallthrough
+	default:
+		// Remaining fields are query parameters.
+		if err := g.mkQueryParameters(op, m.Input.Fields, bound); err != nil {
+			return "", err
+		}
+	}
+	if s != nil {
+		rb := ogen.NewRequestBody().
+			SetRequired(required).
+			SetJSONContent(s)
+		if s.Ref != "" {
+			if err := g.setMediaExamples(rb.Content, m.Input.Desc); err != nil {
+				return "", errors.Wrap(err, "make requestBody examples")
+			}
+		}
+		op.SetRequestBody(rb)
+	}
+	// Sort to make output stable.
+	slices.SortStableFunc(op.Parameters, func(a, b *ogen.Parameter) int {
+		if a.In != b.In {
+			return strings.Compare(a.In, b.In)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return tmpl.String(), nil
+}
+
+// mkRequestBodyComponent generates a shared requestBody component for message body field.
+//
+// Components are named after the field type, so methods using the same message
+// as a body share the component. If a component with different description or
+// requirement already exists, body is inlined instead.
+func (g *Generator) mkRequestBodyComponent(f *protogen.Field, required bool) (ref string, ok bool, _ error) {
+	fd := f.Desc
+	if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+		return "", false, nil
+	}
+	if _, wkt, err := g.mkWellKnownPrimitive(fd.Message()); err != nil || wkt {
+		return "", false, err
+	}
+
+	var (
+		name        = descriptorName(fd.Message())
+		description = mkDescription(f.Comments.Trailing.String())
+	)
+	ref = requestBodyRef(name)
+
+	if rb, ok := g.spec.Components.RequestBodies[name]; ok {
+		if rb.Description != description || rb.Required != required {
+			return "", false, nil
+		}
+		return ref, true, nil
+	}
+
+	g.setRef(name)
+	g.spec.AddRequestBody(name,
+		ogen.NewRequestBody().
+			SetDescription(description).
+			SetRequired(required).
+			SetJSONContent(ogen.NewSchema().SetRef(descriptorRef(fd.Message()))),
+	)
+	return ref, true, nil
+}
+
+func (g *Generator) mkOutput(rule HTTPRule, m *protogen.Method, op *ogen.Operation) error {
+	s := ogen.NewSchema()
+	switch body := rule.ResponseBody; {
+	case (body == "" || body == "*") && isOperation(m.Output.Desc):
+		// Long-running operation, typed by operation_info option.
+		ref, err := g.mkOperationSchema(m)
+		if err != nil {
+			return errors.Wrap(err, "make operation schema")
+		}
+		s.SetRef(ref)
+	case body == "" || body == "*":
+		// Map all response fields.
+		if err := g.mkSchema(m.Output); err != nil {
+			return errors.Wrap(err, "make schema for output")
+		}
+		s.SetRef(descriptorRef(m.Output.Desc))
+	default:
+		// TODO(tdakkota): generate a response component.
+
+		// This field is body, remaining fields are omitted.
+		path, err := resolveFieldPath(m.Output, body)
+		if err != nil {
+			return errors.Wrap(err, "resolve response body")
+		}
+		f := path.Leaf()
+
+		fieldSch, err := g.mkFieldSchema(f.Desc, f.Comments.Leading.String())
+		if err != nil {
+			return errors.Wrapf(err, "make response schema (field: %q)", body)
+		}
+		s = fieldSch
+	}
+	if s != nil && m.Desc.IsStreamingServer() {
+		resp, err := g.mkStreamResponse(m, rule.ResponseBody, s)
+		if err != nil {
+			return errors.Wrap(err, "make stream response")
+		}
+		op.SetResponses(ogen.Responses{"200": resp})
+		return nil
+	}
+	if s != nil {
+		resp := ogen.NewResponse().
+			SetDescription(fmt.Sprintf("%s response", m.Desc.FullName())).
+			SetJSONContent(s)
+		if s.Ref != "" && !isOperation(m.Output.Desc) {
+			if err := g.setMediaExamples(resp.Content, m.Output.Desc); err != nil {
+				return errors.Wrap(err, "make response examples")
+			}
+		}
+		op.SetResponses(ogen.Responses{"200": resp})
+	}
+	return nil
+}
+
+func (g *Generator) mkQueryParameters(op *ogen.Operation, fields []*protogen.Field, bound boundFields) error {
+	type flattenField struct {
+		name  string
+		field *protogen.Field
+	}
+	var flattenFields []flattenField
+
+	// Recursively collect and flatten message type to primitive parameters.
+	//
+	// For example, if path template is "/v1/messages/{message_id}":
+	//
+	//	 message GetMessageRequest {
+	//	 	message SubMessage {
+	//	 	  string subfield = 1;
+	//	 	}
+	//	 	string message_id = 1; // Mapped to URL path.
+	//	 	int64 revision = 2;    // Mapped to URL query parameter `revision`.
+	//	 	SubMessage sub = 3;    // Mapped to URL query parameter `sub.subfield`.
+	//	 }
+	//
+	// See https://cloud.google.com/service-infrastructure/docs/service-management/reference/rpc/google.api#grpc-transcoding.
+	//
+	// Map fields with scalar values are mapped to `deepObject` parameters, e.g. `labels[key]=value`.
+	var (
+		walkFields func(prefix, protoPrefix string, fields []*protogen.Field) error
+		seen       = map[*protogen.Message]int{}
+	)
+	walkFields = func(prefix, protoPrefix string, fields []*protogen.Field) error {
+		for _, f := range fields {
+			fd := f.Desc
+
+			if g.isHiddenField(f) {
+				continue
+			}
+
+			protoName := protoPrefix + string(fd.Name())
+			if bound.Has(protoName) {
+				// Mapped to path parameter or body.
+				continue
+			}
+
+			name := prefix + fd.JSONName()
+
+			switch fd.Kind() {
+			case protoreflect.MessageKind, protoreflect.GroupKind:
+				if fd.IsMap() {
+					switch value := fd.MapValue(); value.Kind() {
+					case protoreflect.MessageKind, protoreflect.GroupKind:
+						return errors.Errorf("query parameter cannot be a map of messages: field %s", name)
+					case protoreflect.EnumKind:
+						g.spec.AddSchema(descriptorName(value.Enum()), g.mkEnumOgenSchema(value.Enum()))
+					}
+					break
+				}
+
+				_, ok, err := g.mkWellKnownPrimitive(fd.Message())
+				if err != nil {
+					return err
+				}
+				if !ok {
+					if fd.IsList() {
+						return errors.Errorf("query parameter cannot be a repeated message: field %s", name)
+					}
+
+					msg := f.Message
+					if seen[msg] > g.queryRecursionLimit {
+						if g.queryRecursionLimit == 0 {
+							return errors.Errorf("query parameter cannot be recursive: field %s", name)
+						}
+						// Recursion limit is reached, do not expand the field further.
+						continue
+					}
+					seen[msg]++
+
+					if err := walkFields(name+".", protoName+".", msg.Fields); err != nil {
+						return err
+					}
+					seen[msg]--
+					continue
+				}
+			case protoreflect.EnumKind:
+				descName := descriptorName(fd.Enum())
+				s := g.mkEnumOgenSchema(fd.Enum())
+				g.spec.AddSchema(descName, s)
+			}
+
+			flattenFields = append(flattenFields, flattenField{name: name, field: f})
+		}
+		return nil
+	}
+	if err := walkFields("", "", fields); err != nil {
+		return err
+	}
+
+	for _, ff := range flattenFields {
+		p, err := g.mkParameter("query", ff.name, ff.field)
+		if err != nil {
+			return err
+		}
+		op.AddParameters(p)
+	}
+
+	return nil
+}
+
+func (g *Generator) mkParameter(in, name string, f *protogen.Field) (*ogen.Parameter, error) {
+	s, err := g.mkFieldSchema(f.Desc, f.Comments.Trailing.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "generate %s parameter %q", in, f.Desc.Name())
+	}
+
+	setFieldFormat(s, f.Desc.Options())
+
+	p := ogen.NewParameter().
+		SetIn(in).
+		SetName(name).
+		SetRequired(isRequired(f.Desc)).
+		SetSchema(s)
+
+	switch in {
+	case "path":
+		p.SetRequired(true)
+	case "query":
+		switch s.Type {
+		case "array":
+			// Explicitly set parameter style to match transcoding spec.
+			p.SetStyle("form").
+				SetExplode(true)
+		case "object":
+			// Map fields are passed as `name[key]=value`.
+			p.SetStyle("deepObject").
+				SetExplode(true)
+		}
+	}
+	return p, nil
+}
+
+// addPathParameter adds path parameter to operation, names of path parameters must be unique.
+func addPathParameter(op *ogen.Operation, p *ogen.Parameter) error {
+	for _, prev := range op.Parameters {
+		if prev.In == "path" && prev.Name == p.Name {
+			return errors.Errorf("path parameter %q is defined more than once", p.Name)
+		}
+	}
+	op.AddParameters(p)
+	return nil
+}
+
+func (g *Generator) hasSchema(s string) bool {
+	_, ok := g.spec.Components.Schemas[s]
+	return ok
+}
+
+// reserveSchema reserves name of schema generated not from message, so
+// message schema of the same name is reported instead of overwritten.
+//
+// Schema may be generated by the same owner more than once.
+func (g *Generator) reserveSchema(name, owner string) error {
+	if prev, ok := g.generated[name]; ok {
+		if prev != owner {
+			return errors.Errorf("schema %q of %s conflicts with generated schema of %s", name, owner, prev)
+		}
+		return nil
+	}
+	if g.hasSchema(name) {
+		return errors.Errorf("schema %q of %s conflicts with existing schema", name, owner)
+	}
+	g.generated[name] = owner
+	return nil
+}
+
+func (g *Generator) setRequest(s string) {
+	if g.hasRequest(s) {
+		return
+	}
+
+	g.requests[s] = struct{}{}
+}
+
+func (g *Generator) hasRequest(s string) bool {
+	_, ok := g.requests[s]
+	return ok
+}
+
+func (g *Generator) setDescriptorName(s string) {
+	if g.hasDescriptorName(s) {
+		return
+	}
+
+	g.descriptorNames[s] = struct{}{}
+}
+
+func (g *Generator) hasDescriptorName(s string) bool {
+	_, ok := g.descriptorNames[s]
+	return ok
+}
+
+func (g *Generator) setRef(r string) {
+	if g.hasRef(r) {
+		return
+	}
+
+	g.refs[r] = struct{}{}
+}
+
+func (g *Generator) hasRef(r string) bool {
+	_, ok := g.refs[r]
+	return ok
+}
@@ -0,0 +1,299 @@
+package gen
+
+import (
+	"io/fs"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/ogen-go/ogen"
+)
+
+// GeneratorOption is option for Generator.
+type GeneratorOption func(g *Generator)
+
+// DefaultOpenAPI is OpenAPI version of generated specification by default.
+const DefaultOpenAPI = "3.1.0"
+
+// WithSpecOpenAPI sets openapi, DefaultOpenAPI is used by default.
+//
+// Output is adjusted to the version: 3.0.x documents use nullable keyword,
+// while 3.1.x documents use "null" type.
+func WithSpecOpenAPI(openapi string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.SetOpenAPI(openapi)
+	}
+}
+
+// WithSpecInfoTitle sets title.
+func WithSpecInfoTitle(title string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetTitle(title)
+	}
+}
+
+// WithSpecInfoDescription sets description.
+func WithSpecInfoDescription(description string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetDescription(description)
+	}
+}
+
+// WithSpecInfoVersion sets version.
+func WithSpecInfoVersion(version string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetVersion(version)
+	}
+}
+
+// WithSpecInfoSummary sets summary.
+//
+// Summary is dropped from OpenAPI 3.0 documents.
+func WithSpecInfoSummary(summary string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.Summary = summary
+	}
+}
+
+// WithSpecInfoTermsOfService sets terms of service URL.
+func WithSpecInfoTermsOfService(url string) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetTermsOfService(url)
+	}
+}
+
+// WithSpecInfoContact sets contact.
+func WithSpecInfoContact(contact *ogen.Contact) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetContact(contact)
+	}
+}
+
+// WithSpecInfoLicense sets license.
+//
+// License identifier is dropped from OpenAPI 3.0 documents.
+func WithSpecInfoLicense(license *ogen.License) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Info.SetLicense(license)
+	}
+}
+
+// WithSpecInfoExtension sets info extension, e.g. x-logo.
+//
+// Value must be encodable as YAML.
+func WithSpecInfoExtension(key string, value any) GeneratorOption {
+	return func(g *Generator) {
+		setExtension(&g.spec.Info.Extensions, key, value)
+	}
+}
+
+// WithIndent sets indent.
+func WithIndent(indent int) GeneratorOption {
+	return func(g *Generator) {
+		g.indent = indent
+	}
+}
+
+// FieldOrder defines order of generated object properties.
+type FieldOrder string
+
+const (
+	// FieldOrderDeclaration orders properties as fields are declared in proto file.
+	FieldOrderDeclaration FieldOrder = "declaration"
+	// FieldOrderNumber orders properties by field number.
+	FieldOrderNumber FieldOrder = "number"
+)
+
+// WithFieldOrder sets order of object properties.
+func WithFieldOrder(order FieldOrder) GeneratorOption {
+	return func(g *Generator) {
+		g.fieldOrder = order
+	}
+}
+
+// WithStreamContentType sets content type of server-streaming method responses.
+//
+// Supported values are ContentTypeNDJSON and ContentTypeEventStream.
+func WithStreamContentType(contentType string) GeneratorOption {
+	return func(g *Generator) {
+		g.streamContentType = contentType
+	}
+}
+
+// WithStreamingPolicy sets handling of client and bidirectional streaming methods.
+func WithStreamingPolicy(policy StreamingPolicy) GeneratorOption {
+	return func(g *Generator) {
+		g.streamingPolicy = policy
+	}
+}
+
+// WithQueryRecursionLimit sets how many times a recursive message
+// is expanded into query parameters.
+//
+// Zero (the default) makes recursive query parameters an error,
+// otherwise fields beyond the limit are omitted.
+func WithQueryRecursionLimit(limit int) GeneratorOption {
+	return func(g *Generator) {
+		g.queryRecursionLimit = limit
+	}
+}
+
+// SpecHook post-processes generated specification.
+type SpecHook func(spec *ogen.Spec) error
+
+// WithSpecHook adds hook called after specification is generated.
+//
+// Hooks are called in order they are added, an error returned by hook
+// is returned by NewGenerator.
+func WithSpecHook(hook SpecHook) GeneratorOption {
+	return func(g *Generator) {
+		g.hooks = append(g.hooks, hook)
+	}
+}
+
+// WithServiceFilter adds filter of services, methods of services
+// for which any filter returns false are not generated.
+func WithServiceFilter(filter func(s *protogen.Service) bool) GeneratorOption {
+	return func(g *Generator) {
+		g.serviceFilters = append(g.serviceFilters, filter)
+	}
+}
+
+// ServiceOverride is a set of settings applied to operations of a service.
+type ServiceOverride struct {
+	// Tags are added to operations.
+	Tags []string
+	// Security overrides document security requirements.
+	Security ogen.SecurityRequirements
+}
+
+// WithServiceOverride sets settings of operations of the service.
+func WithServiceOverride(service protoreflect.FullName, override ServiceOverride) GeneratorOption {
+	return func(g *Generator) {
+		g.serviceOverrides[service] = override
+	}
+}
+
+// OperationIDNaming defines how operationId is derived from method.
+type OperationIDNaming string
+
+const (
+	// OperationIDNamingMethod uses lowerCamelCased method name, e.g. getItem.
+	OperationIDNamingMethod OperationIDNaming = "method"
+	// OperationIDNamingServiceMethod prefixes method name with service name, e.g. itemServiceGetItem.
+	OperationIDNamingServiceMethod OperationIDNaming = "service_method"
+)
+
+// WithOperationIDNaming sets naming of operationId.
+func WithOperationIDNaming(naming OperationIDNaming) GeneratorOption {
+	return func(g *Generator) {
+		g.operationIDNaming = naming
+	}
+}
+
+// WithVisibilityLabels selects visibility labels of google.api.visibility rules.
+//
+// Fields, messages and enum values with visibility restriction are generated
+// only if one of restrictions is selected. Without this option INTERNAL elements
+// are hidden unless they are also marked as PREVIEW.
+func WithVisibilityLabels(labels ...string) GeneratorOption {
+	return func(g *Generator) {
+		g.visibilityLabels = append(make([]string, 0, len(labels)), labels...)
+	}
+}
+
+// WithSpecServers adds servers.
+func WithSpecServers(servers ...*ogen.Server) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.AddServers(servers...)
+	}
+}
+
+// WithSpecSecurityScheme adds security scheme to components.
+func WithSpecSecurityScheme(name string, scheme *ogen.SecurityScheme) GeneratorOption {
+	return func(g *Generator) {
+		if g.spec.Components.SecuritySchemes == nil {
+			g.spec.Components.SecuritySchemes = make(map[string]*ogen.SecurityScheme)
+		}
+		g.spec.Components.SecuritySchemes[name] = scheme
+	}
+}
+
+// WithSpecSecurity sets document security requirements.
+func WithSpecSecurity(security ogen.SecurityRequirements) GeneratorOption {
+	return func(g *Generator) {
+		g.spec.Security = security
+	}
+}
+
+// WithBase sets base OpenAPI document (YAML or JSON) to merge generated paths
+// and components into, conflicts are resolved according to policy.
+//
+// Merged document is validated with ogen parser.
+func WithBase(base []byte, policy MergePolicy) GeneratorOption {
+	return func(g *Generator) {
+		g.base = base
+		g.basePolicy = policy
+	}
+}
+
+// WithPatch adds OpenAPI Overlay 1.0 document or RFC 6902 JSON Patch (YAML or JSON)
+// applied to generated specification, name is used in error messages.
+//
+// Patches are applied in order they are added to emitted document, after base
+// document is merged and specification is adjusted to target OpenAPI version,
+// so OpenAPI 3.0 output is patched with nullable instead of type arrays.
+// Overlay targets are JSONPath queries, every target must match.
+func WithPatch(name string, data []byte) GeneratorOption {
+	return func(g *Generator) {
+		g.patches = append(g.patches, patch{name: name, data: data})
+	}
+}
+
+// WithExamples sets file system with message example files.
+//
+// Example of message is read from <full name>.json or <full name>.textproto,
+// named request and response examples are read from <full name>/<example name>.json
+// or <full name>/<example name>.textproto.
+func WithExamples(fsys fs.FS) GeneratorOption {
+	return func(g *Generator) {
+		g.exampleFS = fsys
+	}
+}
+
+// WithFieldMaskPaths enables listing of valid google.protobuf.FieldMask paths.
+//
+// Paths are derived from the resource message, the only singular message
+// field next to the mask, and set to x-field-mask-paths extension.
+func WithFieldMaskPaths(enabled bool) GeneratorOption {
+	return func(g *Generator) {
+		g.fieldMaskPaths = enabled
+	}
+}
+
+// WithMaxPageSize sets maximum of page_size field of AIP-158 list methods.
+//
+// Defaults to DefaultMaxPageSize.
+func WithMaxPageSize(n int) GeneratorOption {
+	return func(g *Generator) {
+		g.maxPageSize = n
+	}
+}
+
+// WithOperationsPaths enables google.longrunning.Operations GetOperation and
+// ListOperations paths under prefix, e.g. "/v1".
+func WithOperationsPaths(prefix string) GeneratorOption {
+	return func(g *Generator) {
+		g.operationsPrefix = prefix
+	}
+}
+
+// WithSharedParameters moves path and query parameters used by at least n
+// operations to components.parameters.
+//
+// Zero disables sharing.
+func WithSharedParameters(n int) GeneratorOption {
+	return func(g *Generator) {
+		g.sharedParameters = n
+	}
+}
@@ -0,0 +1,592 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/go-faster/sdk/gold"
+	"github.com/ogen-go/ogen"
+)
+
+func TestMain(m *testing.M) {
+	// Explicitly registering flags for golden files.
+	gold.Init()
+
+	os.Exit(m.Run())
+}
+
+// testOptions are additional generator options for specific test cases.
+var testOptions = map[string][]GeneratorOption{
+	"streaming":         {WithStreamingPolicy(StreamingPolicySkip)},
+	"map_query_params":  {WithQueryRecursionLimit(1)},
+	"shared_parameters": {WithSharedParameters(2)},
+	"longrunning":       {WithOperationsPaths("/v1")},
+}
+
+// testRequests adjust requests of specific test cases with options
+// which cannot be written in fixture.
+var testRequests = map[string]func(req *pluginpb.CodeGeneratorRequest){
+	"longrunning": func(req *pluginpb.CodeGeneratorRequest) {
+		setOperationInfos(req, longrunningInfos)
+	},
+}
+
+// testVariants are test cases generated from fixture of another test case
+// with different options.
+var testVariants = []struct {
+	name    string
+	fixture string
+	options []GeneratorOption
+}{
+	{"wrappers_openapi_3_0", "wrappers", []GeneratorOption{WithSpecOpenAPI("3.0.3")}},
+	{"wrappers_openapi_3_0_patch", "wrappers", []GeneratorOption{
+		WithSpecOpenAPI("3.0.3"),
+		WithPatch("nullable.json", []byte(`[
+			{"op": "test", "path": "/components/schemas/Item/properties/name/nullable", "value": true},
+			{"op": "remove", "path": "/components/schemas/Item/properties/name/nullable"}
+		]`)),
+	}},
+	{"field_order_number", "field_order", []GeneratorOption{WithFieldOrder(FieldOrderNumber), WithIndent(4)}},
+	{"field_mask_paths", "field_mask", []GeneratorOption{WithFieldMaskPaths(true)}},
+	{"info_openapi_3_0", "info", []GeneratorOption{
+		WithSpecOpenAPI("3.0.3"),
+		WithSpecInfoTitle("Library"),
+		WithSpecInfoContact(ogen.NewContact().SetEmail("support@example.com")),
+	}},
+	{"streaming_event_stream", "streaming", []GeneratorOption{
+		WithStreamingPolicy(StreamingPolicySkip),
+		WithStreamContentType(ContentTypeEventStream),
+	}},
+}
+
+func TestNewGenerator(t *testing.T) {
+	t.Parallel()
+
+	dirEntries, err := os.ReadDir("_testdata")
+	require.NoError(t, err)
+
+	// Test case name to fixture name.
+	var (
+		fileNames = make(map[string]string)
+		options   = maps.Clone(testOptions)
+	)
+
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			// Example files and inputs of other tests.
+			continue
+		}
+		n := strings.Split(dirEntry.Name(), ".")[0]
+		fileNames[n] = n
+	}
+	for _, v := range testVariants {
+		fileNames[v.name] = v.fixture
+		options[v.name] = v.options
+	}
+
+	for fileName, fixture := range fileNames {
+		fileName, fixture := fileName, fixture
+
+		t.Run(fileName, func(t *testing.T) {
+			t.Parallel()
+
+			textproto, err := os.ReadFile(fmt.Sprintf("_testdata/%s.textproto", fixture))
+			require.NoError(t, err)
+
+			req := new(pluginpb.CodeGeneratorRequest)
+			err = prototext.Unmarshal(textproto, req)
+			require.NoError(t, err)
+			if f, ok := testRequests[fixture]; ok {
+				f(req)
+			}
+
+			opts := protogen.Options{}
+			p, err := opts.New(req)
+			require.NoError(t, err)
+
+			for i := 0; i < len(p.Files); i++ {
+				p.Files[i].Generate = true
+			}
+
+			genOpts := []GeneratorOption{WithSpecOpenAPI("3.1.0"), WithIndent(2)}
+			genOpts = append(genOpts, options[fileName]...)
+
+			// Config file is applied as it is by protoc plugin.
+			if config := fmt.Sprintf("_testdata/%s.oas.yaml", fileName); fileExists(config) {
+				var params Params
+				params.RegisterFlags(flag.NewFlagSet(fileName, flag.ContinueOnError))
+				params.Config = config
+				require.NoError(t, params.LoadConfig())
+
+				genOpts, err = params.Options()
+				require.NoError(t, err)
+			}
+
+			g, err := NewGenerator(p.Files, genOpts...)
+			require.NoError(t, err)
+
+			yaml, err := g.YAML()
+			require.NoError(t, err)
+
+			jsonBytes, err := g.JSON()
+			require.NoError(t, err)
+
+			var minifiedBuffer bytes.Buffer
+			err = json.Compact(&minifiedBuffer, jsonBytes)
+			require.NoError(t, err)
+
+			// Ensure emitted spec is valid.
+			for _, data := range [][]byte{yaml, jsonBytes} {
+				require.NoError(t, validateDocument(data))
+			}
+
+			// Run go test with -update flag to update golden files.
+			gold.Str(t, string(yaml), fmt.Sprintf("%s.yaml", fileName))
+			gold.Str(t, minifiedBuffer.String(), fmt.Sprintf("%s.json", fileName))
+		})
+	}
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// itemServiceFixture is a service with a single GetItem method shared by
+// error cases which only vary generator options, cases pass either inline
+// request or path to request fixture file as input.
+const itemServiceFixture = "_testdata/errors/item.textproto"
+
+func TestNewGeneratorError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		opts    []GeneratorOption
+		wantErr string
+	}{
+		{
+			"RepeatedMessageQueryParameter",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Request"
+					field: { name: "items" number: 1 label: LABEL_REPEATED type: TYPE_MESSAGE type_name: ".service.v1.Item" json_name: "items" }
+				}
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "Get"
+						input_type: ".service.v1.Request"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { get: "/api/v1/items" } }
+					}
+				}
+			}`,
+			nil,
+			"query parameter cannot be a repeated message: field items",
+		},
+		{
+			"RecursiveQueryParameter",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Request"
+					field: { name: "node" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.Node" json_name: "node" }
+				}
+				message_type: {
+					name: "Node"
+					field: { name: "next" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.Node" json_name: "next" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "Get"
+						input_type: ".service.v1.Request"
+						output_type: ".service.v1.Node"
+						options: { [google.api.http]: { get: "/api/v1/nodes" } }
+					}
+				}
+			}`,
+			nil,
+			"query parameter cannot be recursive: field node.next",
+		},
+		{
+			"UnknownNestedPathParameter",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Request"
+					field: { name: "book" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.Book" json_name: "book" }
+				}
+				message_type: {
+					name: "Book"
+					field: { name: "name" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "name" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "Get"
+						input_type: ".service.v1.Request"
+						output_type: ".service.v1.Book"
+						options: { [google.api.http]: { get: "/api/v1/{book.title}" } }
+					}
+				}
+			}`,
+			nil,
+			`resolve path parameter "book.title": unknown field "book.title"`,
+		},
+		{
+			"BaseConflict",
+			itemServiceFixture,
+			[]GeneratorOption{WithBase([]byte(`{"openapi": "3.1.0", "info": {"title": "", "version": ""}, "components": {"schemas": {"Item": {"type": "string"}}}}`), MergePolicyError)},
+			"merge base: conflict on components.schemas.Item: defined by both base and generated documents",
+		},
+		{
+			"BaseInfoConflict",
+			itemServiceFixture,
+			[]GeneratorOption{
+				WithSpecInfoTitle("Generated"),
+				WithBase([]byte(`{"openapi": "3.1.0", "info": {"title": "Base", "version": ""}}`), MergePolicyError),
+			},
+			"merge base: conflict on info.title: defined by both base and generated documents",
+		},
+		{
+			"BaseOpenAPIConflict",
+			itemServiceFixture,
+			[]GeneratorOption{
+				WithSpecOpenAPI("3.1.0"),
+				WithBase([]byte(`{"openapi": "3.0.3", "info": {"title": "Base", "version": "1.0.0"}}`), MergePolicyError),
+			},
+			"merge base: conflict on openapi: defined by both base and generated documents",
+		},
+		{
+			"BaseInvalid",
+			itemServiceFixture,
+			[]GeneratorOption{WithBase([]byte(`{"openapi": "3.1.0", "info": {"title": "", "version": ""}, "security": [{"missing": []}]}`), MergePolicyError)},
+			"merge base: validate document",
+		},
+		{
+			"PatchTargetMismatch",
+			itemServiceFixture,
+			[]GeneratorOption{WithPatch("overlay.yaml", []byte("overlay: 1.0.0\nactions:\n  - target: $.paths['/api/v1/items'].get\n    update:\n      summary: Items\n"))},
+			`apply patches: patch "overlay.yaml": action 0: target "$.paths['/api/v1/items'].get" matches nothing`,
+		},
+		{
+			"PatchPathNotFound",
+			itemServiceFixture,
+			[]GeneratorOption{WithPatch("patch.json", []byte(`[{"op": "replace", "path": "/paths/~1api~1v1~1items/get/summary", "value": "Items"}]`))},
+			`apply patches: patch "patch.json": operation 0 (replace /paths/~1api~1v1~1items/get/summary): path not found: no key "/api/v1/items"`,
+		},
+		{
+			"ExampleFieldMismatch",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+					field: { name: "count" number: 2 label: LABEL_OPTIONAL type: TYPE_INT32 json_name: "count" options: { [oas.field]: { example: "\"many\"" } } }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "GetItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+					}
+				}
+			}`,
+			nil,
+			`generate query parameter "count": make example: parse example`,
+		},
+		{
+			"ExampleHiddenField",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+					field: { name: "secret" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "secret" options: { [google.api.field_visibility]: { restriction: "INTERNAL" } } }
+					options: { [oas.schema]: { example: "{\"id\": \"1\", \"secret\": \"s\"}" } }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "GetItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+					}
+				}
+			}`,
+			nil,
+			`invalid example of service.v1.Item: $.secret: unknown property`,
+		},
+		{
+			"ExampleOneofMissing",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "book" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.Book" json_name: "book" oneof_index: 0 options: { [google.api.field_behavior]: REQUIRED } }
+					field: { name: "film" number: 2 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.Film" json_name: "film" oneof_index: 0 }
+					oneof_decl: { name: "kind" }
+					options: { [oas.schema]: { example: "{}" } }
+				}
+				message_type: { name: "Book" }
+				message_type: { name: "Film" }
+				service: {
+					name: "Service"
+					method: {
+						name: "CreateItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { post: "/api/v1/items" body: "*" } }
+					}
+				}
+			}`,
+			nil,
+			`invalid example of service.v1.Item: $: one of ["book" "film"] is required`,
+		},
+		{
+			"OneofVariantConflict",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "book" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.ItemKindBook" json_name: "book" oneof_index: 0 options: { [google.api.field_behavior]: REQUIRED } }
+					oneof_decl: { name: "kind" }
+				}
+				message_type: { name: "ItemKindBook" }
+				service: {
+					name: "Service"
+					method: {
+						name: "CreateItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { post: "/api/v1/items" body: "*" } }
+					}
+				}
+			}`,
+			nil,
+			`schema "ItemKindBook" conflicts with generated schema of oneof field "service.v1.Item.book"`,
+		},
+		{
+			"JSONNameConflict",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				syntax: "editions"
+				edition: EDITION_2023
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "foo_bar" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "fooBar" }
+					field: { name: "fooBar" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "fooBar" }
+					options: { features: { json_format: LEGACY_BEST_EFFORT } }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "CreateItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { post: "/api/v1/items" body: "*" } }
+					}
+				}
+			}`,
+			nil,
+			`field "service.v1.Item.fooBar" JSON name "fooBar" conflicts with another field (json_format is LEGACY_BEST_EFFORT)`,
+		},
+		{
+			"ClientStreaming",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "UploadItems"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						client_streaming: true
+						options: { [google.api.http]: { post: "/api/v1/items:upload" body: "*" } }
+					}
+				}
+			}`,
+			nil,
+			`client streaming method service.v1.Service.UploadItems is not supported`,
+		},
+		{
+			"StreamErrorConflict",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "error" number: 1 label: LABEL_OPTIONAL type: TYPE_MESSAGE type_name: ".service.v1.StreamError" json_name: "error" }
+				}
+				message_type: {
+					name: "StreamError"
+					field: { name: "reason" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "reason" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "WatchItems"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						server_streaming: true
+						options: { [google.api.http]: { post: "/api/v1/items:watch" body: "*" } }
+					}
+				}
+			}`,
+			nil,
+			`schema "StreamError" conflicts with generated schema of google.rpc.Status`,
+		},
+		{
+			"ExampleFile",
+			itemServiceFixture,
+			[]GeneratorOption{WithExamples(fstest.MapFS{
+				"service.v1.Item/bad.textproto": {Data: []byte(`title: "x"`)},
+			})},
+			`make response examples: example file "service.v1.Item/bad.textproto": parse example`,
+		},
+		{
+			"HeaderContentType",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "GetItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: {
+							[google.api.http]: { get: "/api/v1/items/{id}" }
+							[oas.operation]: { request_headers: { name: "content-type" } }
+						}
+					}
+				}
+			}`,
+			nil,
+			`make headers: request header "content-type" is defined by OpenAPI, use content or security schemes`,
+		},
+		{
+			"HeaderName",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+				}
+				service: {
+					name: "Service"
+					options: { [oas.service]: { response_headers: { name: "X Trace" } } }
+					method: {
+						name: "GetItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+					}
+				}
+			}`,
+			nil,
+			`make headers: response header: invalid header name "X Trace"`,
+		},
+		{
+			"ResourceTemplateNonString",
+			`proto_file: {
+				name: "service.proto"
+				package: "service.v1"
+				options: { go_package: "service/v1;service" }
+				message_type: {
+					name: "Item"
+					field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_INT64 json_name: "id" }
+				}
+				service: {
+					name: "Service"
+					method: {
+						name: "GetItem"
+						input_type: ".service.v1.Item"
+						output_type: ".service.v1.Item"
+						options: { [google.api.http]: { get: "/api/v1/{id=items/*}" } }
+					}
+				}
+			}`,
+			nil,
+			`path parameter "id": segments template "items/*" requires string field`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := []byte(tt.input)
+			if strings.HasSuffix(tt.input, ".textproto") {
+				var err error
+				input, err = os.ReadFile(tt.input)
+				require.NoError(t, err)
+			}
+
+			req := new(pluginpb.CodeGeneratorRequest)
+			require.NoError(t, prototext.Unmarshal(input, req))
+
+			p, err := protogen.Options{}.New(req)
+			require.NoError(t, err)
+			for _, f := range p.Files {
+				f.Generate = true
+			}
+
+			_, err = NewGenerator(p.Files, tt.opts...)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package gen
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+
+	"github.com/ogen-go/protoc-gen-oas/oas"
+)
+
+// operationHeaders returns request and response headers of method,
+// method headers replace service headers with the same name.
+func operationHeaders(m *protogen.Method) (request, response []*oas.Header) {
+	service, _ := proto.GetExtension(m.Parent.Desc.Options(), oas.E_Service).(*oas.Operation)
+	method, _ := proto.GetExtension(m.Desc.Options(), oas.E_Operation).(*oas.Operation)
+
+	merge := func(base, override []*oas.Header) []*oas.Header {
+		headers := slices.Clone(base)
+		for _, h := range override {
+			idx := slices.IndexFunc(headers, func(b *oas.Header) bool {
+				return strings.EqualFold(b.GetName(), h.GetName())
+			})
+			if idx >= 0 {
+				headers[idx] = h
+				continue
+			}
+			headers = append(headers, h)
+		}
+		return headers
+	}
+	request = merge(service.GetRequestHeaders(), method.GetRequestHeaders())
+	response = merge(service.GetResponseHeaders(), method.GetResponseHeaders())
+	return request, response
+}
+
+// mkHeaders adds header parameters and response headers declared by
+// oas.service and oas.operation options.
+func (g *Generator) mkHeaders(m *protogen.Method, op *ogen.Operation) error {
+	request, response := operationHeaders(m)
+
+	for _, h := range request {
+		if err := checkHeader(h); err != nil {
+			return errors.Wrap(err, "request header")
+		}
+		switch http.CanonicalHeaderKey(h.GetName()) {
+		case "Accept", "Content-Type", "Authorization":
+			// See https://spec.openapis.org/oas/v3.1.0#fixed-fields-9.
+			return errors.Errorf("request header %q is defined by OpenAPI, use content or security schemes", h.GetName())
+		}
+
+		p := ogen.NewParameter().
+			InHeader().
+			SetName(h.GetName()).
+			SetDescription(h.GetDescription()).
+			SetRequired(h.GetRequired()).
+			SetSchema(headerSchema(h))
+		if ref, ok := g.mkParameterComponent(h.GetName(), p); ok {
+			p = ogen.NewParameter().SetRef(ref)
+		}
+		op.AddParameters(p)
+	}
+
+	if len(response) == 0 {
+		return nil
+	}
+	headers := make(map[string]*ogen.Header, len(response))
+	for _, h := range response {
+		if err := checkHeader(h); err != nil {
+			return errors.Wrap(err, "response header")
+		}
+
+		header := &ogen.Header{
+			Description: h.GetDescription(),
+			Required:    h.GetRequired(),
+			Schema:      headerSchema(h),
+		}
+		if ref, ok := g.mkHeaderComponent(h.GetName(), header); ok {
+			header = &ogen.Header{Ref: ref}
+		}
+		headers[h.GetName()] = header
+	}
+	for _, resp := range op.Responses {
+		resp.SetHeaders(headers)
+	}
+	return nil
+}
+
+func checkHeader(h *oas.Header) error {
+	name := h.GetName()
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if strings.ContainsFunc(name, func(r rune) bool {
+		// Token characters, see RFC 9110, section 5.6.2.
+		return r <= ' ' || r >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r)
+	}) {
+		return errors.Errorf("invalid header name %q", name)
+	}
+	return nil
+}
+
+func headerSchema(h *oas.Header) *ogen.Schema {
+	return ogen.NewSchema().
+		SetType("string").
+		SetFormat(h.GetFormat()).
+		SetPattern(h.GetPattern())
+}
+
+// componentNameRegexp matches valid component names.
+var componentNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+
+// mkParameterComponent shares parameter through components.parameters.
+//
+// If name is not a valid component name, or a component with the same name
+// but different definition already exists, parameter is inlined instead.
+func (g *Generator) mkParameterComponent(name string, p *ogen.Parameter) (ref string, ok bool) {
+	if !componentNameRegexp.MatchString(name) {
+		return "", false
+	}
+	if prev, ok := g.spec.Components.Parameters[name]; ok {
+		if !reflect.DeepEqual(prev, p) {
+			return "", false
+		}
+		return parameterRef(name), true
+	}
+	g.spec.Components.Parameters[name] = p
+	return parameterRef(name), true
+}
+
+// mkHeaderComponent shares response header through components.headers.
+//
+// If name is not a valid component name, or a component with the same name
+// but different definition already exists, header is inlined instead.
+func (g *Generator) mkHeaderComponent(name string, h *ogen.Header) (ref string, ok bool) {
+	if !componentNameRegexp.MatchString(name) {
+		return "", false
+	}
+	if prev, ok := g.spec.Components.Headers[name]; ok {
+		if !reflect.DeepEqual(prev, h) {
+			return "", false
+		}
+		return headerRef(name), true
+	}
+	g.spec.Components.Headers[name] = h
+	return headerRef(name), true
+}
+
+func parameterRef(s string) string {
+	return fmt.Sprintf("#/components/parameters/%s", s)
+}
+
+func headerRef(s string) string {
+	return fmt.Sprintf("#/components/headers/%s", s)
+}
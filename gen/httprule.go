@@ -35,10 +35,11 @@ func collectRules(opts protoreflect.ProtoMessage) (rules []HTTPRule) {
 			return
 		}
 		rules = append(rules, HTTPRule{
-			Method:     method(rule),
-			Path:       path(rule),
-			Body:       rule.Body,
-			Additional: additional,
+			Method:       method(rule),
+			Path:         path(rule),
+			Body:         rule.Body,
+			ResponseBody: rule.ResponseBody,
+			Additional:   additional,
 		})
 		for _, binding := range rule.AdditionalBindings {
 			walkRules(binding, true)
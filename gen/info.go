@@ -0,0 +1,96 @@
+package gen
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+
+	"github.com/ogen-go/protoc-gen-oas/oas"
+)
+
+// packageFieldNumber is field number of package in FileDescriptorProto.
+const packageFieldNumber = 2
+
+// mkInfoDefaults fills info fields which are not set by options
+// from (oas.info) file option and package comment.
+func (g *Generator) mkInfoDefaults(files []*protogen.File) error {
+	info := &g.spec.Info
+
+	for _, f := range files {
+		if !f.Generate {
+			continue
+		}
+
+		opt, ok := proto.GetExtension(f.Desc.Options(), oas.E_Info).(*oas.Info)
+		if !ok || opt == nil {
+			continue
+		}
+		if err := mergeInfo(info, opt); err != nil {
+			return errors.Wrapf(err, "file %s: oas.info", f.Desc.Path())
+		}
+	}
+
+	if info.Description == "" {
+		for _, f := range files {
+			if !f.Generate {
+				continue
+			}
+
+			loc := f.Desc.SourceLocations().ByPath(protoreflect.SourcePath{packageFieldNumber})
+			if comment := mkCommentText(loc.LeadingComments); comment != "" {
+				info.SetDescription(comment)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func mergeInfo(info *ogen.Info, opt *oas.Info) error {
+	set := func(to *string, value string) {
+		if *to == "" {
+			*to = value
+		}
+	}
+	set(&info.Title, opt.GetTitle())
+	set(&info.Summary, opt.GetSummary())
+	set(&info.Description, opt.GetDescription())
+	set(&info.TermsOfService, opt.GetTermsOfService())
+	set(&info.Version, opt.GetVersion())
+
+	if c := opt.GetContact(); c != nil && info.Contact == nil {
+		info.SetContact(ogen.NewContact().SetName(c.GetName()).SetURL(c.GetUrl()).SetEmail(c.GetEmail()))
+	}
+	if l := opt.GetLicense(); l != nil && info.License == nil {
+		info.SetLicense(&ogen.License{Name: l.GetName(), Identifier: l.GetIdentifier(), URL: l.GetUrl()})
+	}
+
+	for key, value := range opt.GetExtensions() {
+		if !strings.HasPrefix(key, "x-") {
+			return errors.Errorf("extension %q must start with \"x-\"", key)
+		}
+		if _, ok := info.Extensions[key]; ok {
+			continue
+		}
+		setExtension(&info.Extensions, key, value.AsInterface())
+	}
+
+	return nil
+}
+
+// mkCommentText returns text of multiline comment, removing space
+// after comment marker from each line.
+func mkCommentText(comment string) string {
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
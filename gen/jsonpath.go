@@ -0,0 +1,397 @@
+package gen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+)
+
+// nodeRef is a node found in document along with its position.
+type nodeRef struct {
+	node   *yaml.Node
+	parent *yaml.Node
+	// index of node in parent content.
+	index int
+}
+
+// jsonPath is a parsed JSONPath (RFC 9535) query.
+//
+// Supported subset: root ($), child (.name, ['name']), index ([0], [-1]),
+// wildcard (.*, [*]), union ([a,b]), descendant (..name) segments and
+// filters ([?@.a.b == 'v'], [?@.a]) with ==, != comparisons of literals.
+type jsonPath struct {
+	segments []pathSegment
+}
+
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+type pathSelector struct {
+	name     *string
+	index    *int
+	wildcard bool
+	filter   *pathFilter
+}
+
+type pathFilter struct {
+	path []string
+	op   string // "", "==" or "!="
+	lit  *yaml.Node
+}
+
+func parseJSONPath(s string) (*jsonPath, error) {
+	p := &pathParser{s: strings.TrimSpace(s)}
+	q, err := p.parse()
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse JSONPath %q", s)
+	}
+	return q, nil
+}
+
+type pathParser struct {
+	s   string
+	pos int
+}
+
+func (p *pathParser) parse() (*jsonPath, error) {
+	if !p.consume("$") {
+		return nil, errors.New("query must start with $")
+	}
+
+	q := new(jsonPath)
+	for p.pos < len(p.s) {
+		var seg pathSegment
+		switch {
+		case p.consume(".."):
+			seg.descendant = true
+			if p.peek() == '[' {
+				sel, err := p.bracket()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = sel
+			} else {
+				sel, err := p.dotSelector()
+				if err != nil {
+					return nil, err
+				}
+				seg.selectors = []pathSelector{sel}
+			}
+		case p.consume("."):
+			sel, err := p.dotSelector()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []pathSelector{sel}
+		case p.peek() == '[':
+			sel, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = sel
+		default:
+			return nil, errors.Errorf("unexpected %q at %d", p.s[p.pos], p.pos)
+		}
+		q.segments = append(q.segments, seg)
+	}
+	return q, nil
+}
+
+func (p *pathParser) peek() byte {
+	if p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *pathParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) skipSpace() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *pathParser) dotSelector() (pathSelector, error) {
+	if p.consume("*") {
+		return pathSelector{wildcard: true}, nil
+	}
+	name := p.ident()
+	if name == "" {
+		return pathSelector{}, errors.Errorf("expected name at %d", p.pos)
+	}
+	return pathSelector{name: &name}, nil
+}
+
+func (p *pathParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '.' || c == '[' || c == ']' || c == ' ' || c == '=' || c == '!' || c == ')' {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *pathParser) bracket() ([]pathSelector, error) {
+	p.consume("[")
+
+	var selectors []pathSelector
+	for {
+		p.skipSpace()
+		sel, err := p.bracketSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, sel)
+		p.skipSpace()
+
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, errors.Errorf("expected ',' or ']' at %d", p.pos)
+		}
+	}
+}
+
+func (p *pathParser) bracketSelector() (pathSelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return pathSelector{wildcard: true}, nil
+	case c == '\'' || c == '"':
+		name, err := p.quoted()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{name: &name}, nil
+	case c == '?':
+		p.pos++
+		f, err := p.filter()
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{filter: f}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		idx, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return pathSelector{}, errors.Wrap(err, "index")
+		}
+		return pathSelector{index: &idx}, nil
+	default:
+		return pathSelector{}, errors.Errorf("unexpected %q at %d", c, p.pos)
+	}
+}
+
+func (p *pathParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return b.String(), nil
+		case '\\':
+			if p.pos < len(p.s) {
+				b.WriteByte(p.s[p.pos])
+				p.pos++
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *pathParser) filter() (*pathFilter, error) {
+	p.skipSpace()
+	paren := p.consume("(")
+	p.skipSpace()
+	if !p.consume("@") {
+		return nil, errors.Errorf("filter must start with @ at %d", p.pos)
+	}
+
+	f := new(pathFilter)
+	for {
+		switch {
+		case p.consume("."):
+			name := p.ident()
+			if name == "" {
+				return nil, errors.Errorf("expected name at %d", p.pos)
+			}
+			f.path = append(f.path, name)
+			continue
+		case p.peek() == '[':
+			p.pos++
+			name, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			if !p.consume("]") {
+				return nil, errors.Errorf("expected ']' at %d", p.pos)
+			}
+			f.path = append(f.path, name)
+			continue
+		}
+		break
+	}
+
+	p.skipSpace()
+	for _, op := range []string{"==", "!="} {
+		if !p.consume(op) {
+			continue
+		}
+		f.op = op
+		p.skipSpace()
+
+		var lit yaml.Node
+		if c := p.peek(); c == '\'' || c == '"' {
+			s, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			lit = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+		} else {
+			s := p.ident()
+			if err := yaml.Unmarshal([]byte(s), &lit); err != nil || len(lit.Content) != 1 {
+				return nil, errors.Errorf("invalid literal %q", s)
+			}
+			lit = *lit.Content[0]
+		}
+		f.lit = &lit
+		break
+	}
+
+	p.skipSpace()
+	if paren && !p.consume(")") {
+		return nil, errors.Errorf("expected ')' at %d", p.pos)
+	}
+	return f, nil
+}
+
+// eval returns nodes matching query in document order.
+func (q *jsonPath) eval(root *yaml.Node) []nodeRef {
+	refs := []nodeRef{{node: root, index: -1}}
+	for _, seg := range q.segments {
+		var next []nodeRef
+		for _, ref := range refs {
+			if seg.descendant {
+				walkDescendants(ref, func(d nodeRef) {
+					next = append(next, seg.apply(d)...)
+				})
+				continue
+			}
+			next = append(next, seg.apply(ref)...)
+		}
+		refs = next
+	}
+	return refs
+}
+
+func walkDescendants(ref nodeRef, f func(ref nodeRef)) {
+	f(ref)
+	n := ref.node
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			walkDescendants(nodeRef{node: n.Content[i], parent: n, index: i}, f)
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			walkDescendants(nodeRef{node: child, parent: n, index: i}, f)
+		}
+	}
+}
+
+func (seg pathSegment) apply(ref nodeRef) (result []nodeRef) {
+	n := ref.node
+	for _, sel := range seg.selectors {
+		switch {
+		case sel.name != nil:
+			if n.Kind != yaml.MappingNode {
+				continue
+			}
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == *sel.name {
+					result = append(result, nodeRef{node: n.Content[i+1], parent: n, index: i + 1})
+				}
+			}
+		case sel.index != nil:
+			if n.Kind != yaml.SequenceNode {
+				continue
+			}
+			idx := *sel.index
+			if idx < 0 {
+				idx += len(n.Content)
+			}
+			if idx >= 0 && idx < len(n.Content) {
+				result = append(result, nodeRef{node: n.Content[idx], parent: n, index: idx})
+			}
+		default:
+			result = append(result, children(n, sel.filter)...)
+		}
+	}
+	return result
+}
+
+// children returns children of node, matching filter if any.
+func children(n *yaml.Node, f *pathFilter) (result []nodeRef) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if f.match(n.Content[i]) {
+				result = append(result, nodeRef{node: n.Content[i], parent: n, index: i})
+			}
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			if f.match(child) {
+				result = append(result, nodeRef{node: child, parent: n, index: i})
+			}
+		}
+	}
+	return result
+}
+
+func (f *pathFilter) match(n *yaml.Node) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, name := range f.path {
+		n = mappingValue(n, name)
+		if n == nil {
+			return f.op == "!="
+		}
+	}
+
+	switch f.op {
+	case "==":
+		return equalNodes(n, f.lit)
+	case "!=":
+		return !equalNodes(n, f.lit)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,64 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/go-faster/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONPath(t *testing.T) {
+	t.Parallel()
+
+	const doc = `
+paths:
+  /a:
+    get: {operationId: getA, tags: [x]}
+    post: {operationId: createA}
+  /b:
+    get: {operationId: getB, deprecated: true}
+components:
+  schemas:
+    A: {type: object, properties: {id: {type: string}}}
+`
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(doc), &root))
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{`$.paths['/a'].get.operationId`, []string{"getA"}},
+		{`$.paths["/b"].get.operationId`, []string{"getB"}},
+		{`$.paths.*.get.operationId`, []string{"getA", "getB"}},
+		{`$.paths['/a']['get', 'post'].operationId`, []string{"getA", "createA"}},
+		{`$..operationId`, []string{"getA", "createA", "getB"}},
+		{`$.paths.*[?@.deprecated == true].operationId`, []string{"getB"}},
+		{`$.paths.*[?(@.operationId != 'getA')].operationId`, []string{"createA", "getB"}},
+		{`$.paths.*[?@.tags].operationId`, []string{"getA"}},
+		{`$.paths['/a'].get.tags[0]`, []string{"x"}},
+		{`$.paths['/a'].get.tags[-1]`, []string{"x"}},
+		{`$..properties.id.type`, []string{"string"}},
+		{`$.paths['/c']`, nil},
+	}
+	for _, tt := range tests {
+		q, err := parseJSONPath(tt.query)
+		require.NoError(t, err, tt.query)
+
+		var got []string
+		for _, ref := range q.eval(root.Content[0]) {
+			got = append(got, ref.node.Value)
+		}
+		require.Equal(t, tt.want, got, tt.query)
+	}
+
+	for _, query := range []string{
+		`paths`,
+		`$.paths[`,
+		`$.paths['/a`,
+		`$[?foo]`,
+	} {
+		_, err := parseJSONPath(query)
+		require.Error(t, err, query)
+	}
+}
@@ -0,0 +1,422 @@
+package gen
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+	"github.com/ogen-go/ogen/openapi/parser"
+)
+
+// LintRule is a name of lint rule.
+type LintRule string
+
+const (
+	// LintMissingDescription reports operations, schemas and properties without description.
+	LintMissingDescription LintRule = "missing-description"
+	// LintDuplicateOperationID reports operations sharing operationId.
+	LintDuplicateOperationID LintRule = "duplicate-operation-id"
+	// LintUnusedSchema reports component schemas which are not referenced.
+	LintUnusedSchema LintRule = "unused-schema"
+	// LintAmbiguousPath reports paths which differ only in parameter names.
+	LintAmbiguousPath LintRule = "ambiguous-path"
+	// LintResourcePattern reports path variables not matching patterns of resource.
+	LintResourcePattern LintRule = "resource-pattern"
+	// LintCommentExample reports comment example blocks which are not valid JSON.
+	LintCommentExample LintRule = "comment-example"
+)
+
+// LintRules are all lint rules.
+var LintRules = []LintRule{
+	LintMissingDescription,
+	LintDuplicateOperationID,
+	LintUnusedSchema,
+	LintAmbiguousPath,
+	LintResourcePattern,
+	LintCommentExample,
+}
+
+// LintOptionalRules are lint rules disabled unless enabled explicitly:
+// they report issues in most specifications and would flood the output.
+var LintOptionalRules = []LintRule{
+	LintMissingDescription,
+	LintUnusedSchema,
+}
+
+// LintLevel defines how lint issues are reported.
+type LintLevel string
+
+const (
+	// LintLevelOff disables lint.
+	LintLevelOff LintLevel = "off"
+	// LintLevelWarn reports lint issues without failing generation.
+	LintLevelWarn LintLevel = "warn"
+	// LintLevelError fails generation on lint issues.
+	LintLevelError LintLevel = "error"
+)
+
+// Diagnostic is an issue found in generated specification.
+type Diagnostic struct {
+	Rule LintRule
+	// Pointer is JSON Pointer to the object in specification.
+	Pointer string
+	// Location is proto source location of the object (file:line:column), if known.
+	Location string
+	Message  string
+}
+
+// String returns diagnostic in "location: message (rule)" form.
+func (d Diagnostic) String() string {
+	at := d.Location
+	if at == "" {
+		at = d.Pointer
+	}
+	return fmt.Sprintf("%s: %s (%s)", at, d.Message, d.Rule)
+}
+
+// source is proto definition of specification object.
+type source struct {
+	file protoreflect.FileDescriptor
+	loc  protogen.Location
+}
+
+func (s source) String() string {
+	l := s.file.SourceLocations().ByPath(protoreflect.SourcePath(s.loc.Path))
+	if l.Path == nil {
+		// No source info.
+		return s.loc.SourceFile
+	}
+	return fmt.Sprintf("%s:%d:%d", s.loc.SourceFile, l.StartLine+1, l.StartColumn+1)
+}
+
+// setSource records proto definition of object at JSON Pointer.
+func (g *Generator) setSource(ptr string, file protoreflect.FileDescriptor, loc protogen.Location) {
+	if _, ok := g.sources[ptr]; ok {
+		return
+	}
+	g.sources[ptr] = source{file: file, loc: loc}
+}
+
+// Validate validates emitted specification with ogen parser.
+//
+// Specification is validated as it is written: with base document merged,
+// version rewrites and patches applied.
+func (g *Generator) Validate() error {
+	data, err := g.YAML()
+	if err != nil {
+		return err
+	}
+	if err := validateDocument(data); err != nil {
+		return errors.Wrap(err, "validate spec")
+	}
+	return nil
+}
+
+// validateDocument parses and validates YAML or JSON specification.
+func validateDocument(data []byte) error {
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return errors.Wrap(err, "decode")
+	}
+	if len(n.Content) == 0 {
+		return errors.New("empty document")
+	}
+	// ogen model cannot represent 3.1 type arrays and const,
+	// rewrite them to 3.0 equivalents it decodes.
+	walkSchemas(n.Content[0], rewriteSchema30)
+
+	spec := new(ogen.Spec)
+	if err := n.Decode(spec); err != nil {
+		return errors.Wrap(err, "decode")
+	}
+	spec.Init()
+
+	_, err := parser.Parse(spec, parser.Settings{})
+	return err
+}
+
+// Lint checks generated specification with lint rules except disabled ones.
+func (g *Generator) Lint(disabled ...LintRule) ([]Diagnostic, error) {
+	l := &linter{g: g}
+	for _, rule := range LintRules {
+		if slices.Contains(disabled, rule) {
+			continue
+		}
+
+		var err error
+		switch rule {
+		case LintMissingDescription:
+			l.missingDescriptions()
+		case LintDuplicateOperationID:
+			l.duplicateOperationIDs()
+		case LintUnusedSchema:
+			err = l.unusedSchemas()
+		case LintAmbiguousPath:
+			l.ambiguousPaths()
+		case LintResourcePattern:
+			l.resourcePatterns()
+		case LintCommentExample:
+			l.commentExamples()
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %s", rule)
+		}
+	}
+	return l.diagnostics, nil
+}
+
+type linter struct {
+	g           *Generator
+	diagnostics []Diagnostic
+}
+
+func (l *linter) report(rule LintRule, ptr, format string, args ...any) {
+	d := Diagnostic{
+		Rule:    rule,
+		Pointer: ptr,
+		Message: fmt.Sprintf(format, args...),
+	}
+	if s, ok := l.g.sources[ptr]; ok {
+		d.Location = s.String()
+	}
+	l.diagnostics = append(l.diagnostics, d)
+}
+
+// specOperation is an operation of specification.
+type specOperation struct {
+	path   string
+	method string
+	op     *ogen.Operation
+}
+
+func (o specOperation) pointer() string {
+	return jsonPointer("paths", o.path, strings.ToLower(o.method))
+}
+
+// sortedOperations returns operations sorted by path and method.
+func (g *Generator) sortedOperations() (ops []specOperation) {
+	for _, path := range sortedMapKeys(g.spec.Paths) {
+		pi := g.spec.Paths[path]
+		if pi == nil {
+			continue
+		}
+		for _, e := range []struct {
+			method string
+			op     *ogen.Operation
+		}{
+			{"GET", pi.Get},
+			{"PUT", pi.Put},
+			{"POST", pi.Post},
+			{"DELETE", pi.Delete},
+			{"OPTIONS", pi.Options},
+			{"HEAD", pi.Head},
+			{"PATCH", pi.Patch},
+			{"TRACE", pi.Trace},
+		} {
+			if e.op != nil {
+				ops = append(ops, specOperation{path: path, method: e.method, op: e.op})
+			}
+		}
+	}
+	return ops
+}
+
+func (l *linter) missingDescriptions() {
+	for _, o := range l.g.sortedOperations() {
+		if o.op.Description == "" && o.op.Summary == "" {
+			l.report(LintMissingDescription, o.pointer(), "operation %s %s has no description", o.method, o.path)
+		}
+	}
+
+	schemas := l.g.spec.Components.Schemas
+	for _, name := range sortedMapKeys(schemas) {
+		s := schemas[name]
+		if s == nil || s.Ref != "" {
+			continue
+		}
+		ptr := jsonPointer("components", "schemas", name)
+		if s.Description == "" {
+			l.report(LintMissingDescription, ptr, "schema %s has no description", name)
+		}
+		for _, p := range s.Properties {
+			if p.Schema != nil && p.Schema.Description == "" {
+				l.report(LintMissingDescription, ptr+"/properties/"+escapePointer(p.Name),
+					"property %s of schema %s has no description", p.Name, name)
+			}
+		}
+	}
+}
+
+func (l *linter) duplicateOperationIDs() {
+	seen := make(map[string]specOperation)
+	for _, o := range l.g.sortedOperations() {
+		id := o.op.OperationID
+		if id == "" {
+			continue
+		}
+		if first, ok := seen[id]; ok {
+			l.report(LintDuplicateOperationID, o.pointer(), "operationId %q of %s %s is already used by %s %s",
+				id, o.method, o.path, first.method, first.path)
+			continue
+		}
+		seen[id] = o
+	}
+}
+
+func (l *linter) unusedSchemas() error {
+	root, err := encodeNode(reflect.ValueOf(l.g.spec))
+	if err != nil {
+		return errors.Wrap(err, "encode spec")
+	}
+
+	var (
+		schemas = mappingValue(mappingValue(root, "components"), "schemas")
+		used    = make(map[string]struct{})
+		queue   []string
+	)
+	use := func(n *yaml.Node) {
+		collectRefs(n, func(ref string) {
+			name, ok := strings.CutPrefix(ref, schemaRef(""))
+			if !ok {
+				return
+			}
+			if _, ok := used[name]; ok {
+				return
+			}
+			used[name] = struct{}{}
+			queue = append(queue, name)
+		})
+	}
+
+	// Schemas referenced outside of components.schemas are roots.
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i].Value, root.Content[i+1]
+		if key != "components" {
+			use(val)
+			continue
+		}
+		for j := 0; j+1 < len(val.Content); j += 2 {
+			if val.Content[j].Value != "schemas" {
+				use(val.Content[j+1])
+			}
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if s := mappingValue(schemas, name); s != nil {
+			use(s)
+		}
+	}
+
+	for _, name := range sortedMapKeys(l.g.spec.Components.Schemas) {
+		if _, ok := used[name]; !ok {
+			l.report(LintUnusedSchema, jsonPointer("components", "schemas", name), "schema %s is not used", name)
+		}
+	}
+	return nil
+}
+
+func collectRefs(n *yaml.Node, f func(ref string)) {
+	if n == nil {
+		return
+	}
+	if n.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i].Value, n.Content[i+1]
+			switch key {
+			case "$ref":
+				f(val.Value)
+			case "example", "examples", "default":
+				// Values, not OpenAPI objects.
+			default:
+				collectRefs(val, f)
+			}
+		}
+		return
+	}
+	for _, child := range n.Content {
+		collectRefs(child, f)
+	}
+}
+
+var pathParamRegexp = regexp.MustCompile(`\{[^}]*\}`)
+
+func (l *linter) ambiguousPaths() {
+	// Path items have no proto definition, point to their first operation.
+	pointers := make(map[string]string)
+	for _, o := range l.g.sortedOperations() {
+		if _, ok := pointers[o.path]; !ok {
+			pointers[o.path] = o.pointer()
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, path := range sortedMapKeys(l.g.spec.Paths) {
+		key := pathParamRegexp.ReplaceAllString(path, "{}")
+		first, ok := seen[key]
+		if !ok {
+			seen[key] = path
+			continue
+		}
+
+		ptr, ok := pointers[path]
+		if !ok {
+			ptr = jsonPointer("paths", path)
+		}
+		l.report(LintAmbiguousPath, ptr, "path %s is ambiguous with %s", path, first)
+	}
+}
+
+// jsonPointer returns JSON Pointer (RFC 6901) with given reference tokens.
+func jsonPointer(tokens ...string) string {
+	var b strings.Builder
+	b.WriteByte('#')
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapePointer(t))
+	}
+	return b.String()
+}
+
+func escapePointer(t string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(t)
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// pathIssue is an issue of operation path found by generator.
+type pathIssue struct {
+	path    string
+	method  string
+	message string
+}
+
+func (l *linter) resourcePatterns() {
+	for _, issue := range l.g.pathIssues {
+		l.report(LintResourcePattern, jsonPointer("paths", issue.path, strings.ToLower(issue.method)),
+			"%s %s: %s", issue.method, issue.path, issue.message)
+	}
+}
+
+func (l *linter) commentExamples() {
+	for _, issue := range l.g.exampleIssues {
+		l.report(LintCommentExample, issue.ptr, "%s", issue.message)
+	}
+}
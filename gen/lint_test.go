@@ -0,0 +1,195 @@
+package gen
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func testLintGenerator(t *testing.T, name string, opts ...GeneratorOption) *Generator {
+	t.Helper()
+
+	textproto, err := os.ReadFile(name)
+	require.NoError(t, err)
+
+	req := new(pluginpb.CodeGeneratorRequest)
+	require.NoError(t, prototext.Unmarshal(textproto, req))
+
+	p, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+	for _, f := range p.Files {
+		f.Generate = true
+	}
+
+	g, err := NewGenerator(p.Files, append([]GeneratorOption{WithSpecOpenAPI("3.1.0")}, opts...)...)
+	require.NoError(t, err)
+	return g
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	g := testLintGenerator(t, "_testdata/lint/lint.textproto")
+
+	diagnostics, err := g.Lint()
+	require.NoError(t, err)
+
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.String())
+	}
+	require.Equal(t, []string{
+		"lint.proto:19:3: operation GET /v1/legacy/items/{name} has no description (missing-description)",
+		"lint.proto:37:3: property name of schema Item has no description (missing-description)",
+		"lint.proto:41:1: schema Status has no description (missing-description)",
+		`lint.proto:19:3: operationId "getItem" of GET /v1/legacy/items/{name} is already used by GET /v1/items/{id} (duplicate-operation-id)`,
+		"lint.proto:41:1: schema Status is not used (unused-schema)",
+	}, got)
+
+	diagnostics, err = g.Lint(LintMissingDescription, LintUnusedSchema)
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 1)
+	require.Equal(t, LintDuplicateOperationID, diagnostics[0].Rule)
+	require.Equal(t, "#/paths/~1v1~1legacy~1items~1{name}/get", diagnostics[0].Pointer)
+}
+
+func TestLintAmbiguousPath(t *testing.T) {
+	t.Parallel()
+
+	req := new(pluginpb.CodeGeneratorRequest)
+	require.NoError(t, prototext.Unmarshal([]byte(`proto_file: {
+		name: "service.proto"
+		package: "service.v1"
+		options: { go_package: "service/v1;service" }
+		message_type: {
+			name: "Item"
+			field: { name: "id" number: 1 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "id" }
+			field: { name: "name" number: 2 label: LABEL_OPTIONAL type: TYPE_STRING json_name: "name" }
+		}
+		service: {
+			name: "Service"
+			method: {
+				name: "GetItem"
+				input_type: ".service.v1.Item"
+				output_type: ".service.v1.Item"
+				options: { [google.api.http]: { get: "/api/v1/items/{id}" } }
+			}
+			method: {
+				name: "UpdateItem"
+				input_type: ".service.v1.Item"
+				output_type: ".service.v1.Item"
+				options: { [google.api.http]: { patch: "/api/v1/items/{name}" body: "*" } }
+			}
+		}
+	}`), req))
+
+	p, err := protogen.Options{}.New(req)
+	require.NoError(t, err)
+	for _, f := range p.Files {
+		f.Generate = true
+	}
+	g, err := NewGenerator(p.Files, WithSpecOpenAPI("3.1.0"))
+	require.NoError(t, err)
+
+	var (
+		params = Params{Lint: string(LintLevelWarn)}
+		out    bytes.Buffer
+	)
+	require.ErrorContains(t, params.Check(g, &out), "validate spec")
+	require.Equal(t, "service.proto: path /api/v1/items/{name} is ambiguous with /api/v1/items/{id} (ambiguous-path)\n", out.String())
+}
+
+func TestLintResourcePattern(t *testing.T) {
+	t.Parallel()
+
+	// Path variables not matching resource patterns are still generated.
+	g := testLintGenerator(t, "_testdata/lint/resource_pattern.textproto")
+	require.Contains(t, g.Spec().Paths, "/api/v1/items/{name}")
+
+	diagnostics, err := g.Lint()
+	require.NoError(t, err)
+
+	var got []string
+	for _, d := range diagnostics {
+		if d.Rule == LintResourcePattern {
+			got = append(got, d.String())
+		}
+	}
+	require.Equal(t, []string{
+		`service.proto: GET /api/v1/items/{name}: segments template "items/*" of name does not match resource patterns ["shelves/{shelf}/items/{item}"] (resource-pattern)`,
+		`service.proto: DELETE /api/v1/items/{name}: segments template "*" of name does not match resource patterns ["shelves/{shelf}/items/{item}"] (resource-pattern)`,
+	}, got)
+}
+
+func TestLintCommentExample(t *testing.T) {
+	t.Parallel()
+
+	// Example block which is not valid JSON is kept as description.
+	g := testLintGenerator(t, "_testdata/lint/comment_example.textproto")
+	item := g.Spec().Components.Schemas["Item"]
+	require.Equal(t, "Item is an item.\nExample: shelves/1/items/2", item.Description)
+	require.Nil(t, item.Example)
+	require.JSONEq(t, `"Dune"`, string(item.Properties[1].Schema.Example))
+
+	diagnostics, err := g.Lint()
+	require.NoError(t, err)
+
+	var got []string
+	for _, d := range diagnostics {
+		if d.Rule == LintCommentExample {
+			got = append(got, d.String())
+		}
+	}
+	require.Equal(t, []string{
+		`service.proto:3:1: Example: block "shelves/1/items/2" is not valid JSON, kept as description (comment-example)`,
+	}, got)
+}
+
+func TestCheckLevel(t *testing.T) {
+	t.Parallel()
+
+	g := testLintGenerator(t, "_testdata/lint/lint.textproto")
+	g.Spec().Paths["/v1/legacy/items/{name}"].Get.SetOperationID("getLegacyItem")
+
+	all := []string{string(LintMissingDescription), string(LintUnusedSchema)}
+	for _, tt := range []struct {
+		level   LintLevel
+		enable  []string
+		lines   int
+		wantErr string
+	}{
+		{LintLevelOff, all, 0, ""},
+		{LintLevelWarn, nil, 0, ""},
+		{LintLevelWarn, []string{string(LintUnusedSchema)}, 1, ""},
+		{LintLevelWarn, all, 4, ""},
+		{LintLevelError, nil, 0, ""},
+		{LintLevelError, all, 4, "lint: 4 issue(s) found"},
+	} {
+		var out bytes.Buffer
+		err := Params{Lint: string(tt.level), LintEnable: tt.enable}.Check(g, &out)
+		if tt.wantErr != "" {
+			require.EqualError(t, err, tt.wantErr)
+		} else {
+			require.NoError(t, err)
+		}
+		require.Equal(t, tt.lines, bytes.Count(out.Bytes(), []byte("\n")), tt.level)
+	}
+}
+
+func TestCheckPatch(t *testing.T) {
+	t.Parallel()
+
+	// Patched document is validated as it is written.
+	g := testLintGenerator(t, "_testdata/patch.textproto",
+		WithPatch("patch.json", []byte(`[{"op": "remove", "path": "/components/schemas/Book"}]`)),
+	)
+	require.Contains(t, g.Spec().Components.Schemas, "Book")
+
+	var out bytes.Buffer
+	require.ErrorContains(t, Params{Lint: string(LintLevelOff)}.Check(g, &out), "validate spec")
+}
@@ -0,0 +1,445 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+)
+
+const (
+	operationFullName = "google.longrunning.Operation"
+	// operationInfoNumber is a field number of google.longrunning.operation_info method option.
+	operationInfoNumber = 1049
+
+	operationSchemaName        = "Operation"
+	operationErrorSchemaName   = "OperationError"
+	listOperationsResponseName = "ListOperationsResponse"
+	anySchemaName              = "Any"
+	anyTypeProperty            = "@type"
+	anyTypeURLPrefix           = "type.googleapis.com/"
+)
+
+// operationInfo is google.longrunning.operation_info method option.
+//
+// See https://google.aip.dev/151.
+type operationInfo struct {
+	ResponseType string
+	MetadataType string
+}
+
+// methodOperationInfo returns google.longrunning.operation_info option of method.
+//
+// The option type is usually not linked into the plugin, so protoc passes it
+// as unknown field of method options.
+func methodOperationInfo(opts protoreflect.ProtoMessage) (info operationInfo, ok bool) {
+	if opts == nil {
+		return info, false
+	}
+	m := opts.ProtoReflect()
+	if !m.IsValid() {
+		return info, false
+	}
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.FullName() != "google.longrunning.operation_info" || fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		msg := v.Message()
+		fields := msg.Descriptor().Fields()
+		if f := fields.ByName("response_type"); f != nil {
+			info.ResponseType = msg.Get(f).String()
+		}
+		if f := fields.ByName("metadata_type"); f != nil {
+			info.MetadataType = msg.Get(f).String()
+		}
+		ok = true
+		return false
+	})
+	if ok {
+		return info, true
+	}
+
+	b := m.GetUnknown()
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return info, false
+		}
+		b = b[n:]
+
+		if num == operationInfoNumber && typ == protowire.BytesType {
+			v, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return info, false
+			}
+			b = b[n:]
+			// Embedded message fields are merged.
+			if !info.merge(v) {
+				return info, false
+			}
+			ok = true
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return info, false
+		}
+		b = b[n:]
+	}
+	return info, ok
+}
+
+func (info *operationInfo) merge(b []byte) bool {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+
+		if typ == protowire.BytesType && (num == 1 || num == 2) {
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return false
+			}
+			b = b[n:]
+			if num == 1 {
+				info.ResponseType = v
+			} else {
+				info.MetadataType = v
+			}
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return false
+		}
+		b = b[n:]
+	}
+	return true
+}
+
+// collectMessages indexes messages of all files by full name.
+func (g *Generator) collectMessages(files []*protogen.File) {
+	var walk func(msgs []*protogen.Message)
+	walk = func(msgs []*protogen.Message) {
+		for _, m := range msgs {
+			g.messages[m.Desc.FullName()] = m
+			walk(m.Messages)
+		}
+	}
+	for _, f := range files {
+		walk(f.Messages)
+	}
+}
+
+// resolveMessage finds message by name, unqualified names are resolved
+// relative to the package of method.
+func (g *Generator) resolveMessage(m *protogen.Method, name string) (*protogen.Message, error) {
+	pkg := m.Desc.ParentFile().Package()
+	if msg, ok := g.messages[pkg.Append(protoreflect.Name(name))]; ok {
+		return msg, nil
+	}
+	if msg, ok := g.messages[protoreflect.FullName(name)]; ok {
+		return msg, nil
+	}
+	return nil, errors.Errorf("unknown message %q", name)
+}
+
+func isOperation(md protoreflect.MessageDescriptor) bool {
+	return md.FullName() == operationFullName
+}
+
+// mkOperationSchema generates google.longrunning.Operation schema of method
+// with response and metadata typed by operation_info option.
+//
+// Returns reference to the schema.
+func (g *Generator) mkOperationSchema(m *protogen.Method) (string, error) {
+	info, ok := methodOperationInfo(m.Desc.Options())
+	if !ok {
+		return g.mkGenericOperationSchema()
+	}
+	if info.ResponseType == "" {
+		return "", errors.New("operation_info: response_type is required")
+	}
+	if info.MetadataType == "" {
+		return "", errors.New("operation_info: metadata_type is required")
+	}
+
+	name := "OperationOf" + CamelCase(g.operationID(m))
+	if prev, ok := g.operations[name]; ok {
+		if prev != info {
+			return "", errors.Errorf("operation schema %q conflicts with another method", name)
+		}
+		return schemaRef(name), nil
+	}
+	if err := g.reserveSchema(name, operationFullName); err != nil {
+		return "", err
+	}
+	g.operations[name] = info
+
+	response, err := g.resolveMessage(m, info.ResponseType)
+	if err != nil {
+		return "", errors.Wrap(err, "operation_info: response_type")
+	}
+	metadata, err := g.resolveMessage(m, info.MetadataType)
+	if err != nil {
+		return "", errors.Wrap(err, "operation_info: metadata_type")
+	}
+
+	responseSchema, err := g.mkTypedAny(response)
+	if err != nil {
+		return "", errors.Wrap(err, "make operation response")
+	}
+	metadataSchema, err := g.mkTypedAny(metadata)
+	if err != nil {
+		return "", errors.Wrap(err, "make operation metadata")
+	}
+
+	s, err := g.operationSchema(
+		fmt.Sprintf("Long-running operation of %s.", m.Desc.FullName()),
+		metadataSchema,
+		responseSchema,
+	)
+	if err != nil {
+		return "", err
+	}
+	g.spec.AddSchema(name, s)
+	return schemaRef(name), nil
+}
+
+// operationSchema returns google.longrunning.Operation schema with given metadata and response.
+func (g *Generator) operationSchema(description string, metadata, response *ogen.Schema) (*ogen.Schema, error) {
+	if err := g.mkStatusSchema(operationErrorSchemaName, "Operation error, see google.rpc.Status."); err != nil {
+		return nil, err
+	}
+
+	s := ogen.NewSchema().
+		SetType("object").
+		SetDescription(description)
+	s.AddOptionalProperties(
+		&ogen.Property{
+			Name: "name",
+			Schema: ogen.NewSchema().
+				SetType("string").
+				SetDescription("Server-assigned name of the operation."),
+		},
+		&ogen.Property{Name: "metadata", Schema: metadata},
+		&ogen.Property{
+			Name: "done",
+			Schema: ogen.NewSchema().
+				SetType("boolean").
+				SetDescription("Whether the operation is completed, either with error or response."),
+		},
+		&ogen.Property{Name: "error", Schema: ogen.NewSchema().SetRef(schemaRef(operationErrorSchemaName))},
+		&ogen.Property{Name: "response", Schema: response},
+	)
+	return s, nil
+}
+
+// mkGenericOperationSchema generates google.longrunning.Operation schema
+// with untyped response and metadata.
+func (g *Generator) mkGenericOperationSchema() (string, error) {
+	ref := schemaRef(operationSchemaName)
+	if g.generated[operationSchemaName] != "" {
+		return ref, nil
+	}
+	if err := g.reserveSchema(operationSchemaName, operationFullName); err != nil {
+		return "", err
+	}
+
+	if err := g.mkAnySchema(); err != nil {
+		return "", err
+	}
+	s, err := g.operationSchema(
+		"Long-running operation.",
+		ogen.NewSchema().SetRef(schemaRef(anySchemaName)),
+		ogen.NewSchema().SetRef(schemaRef(anySchemaName)),
+	)
+	if err != nil {
+		return "", err
+	}
+	g.spec.AddSchema(operationSchemaName, s)
+	return ref, nil
+}
+
+// mkAnySchema generates schema of google.protobuf.Any of unknown type.
+func (g *Generator) mkAnySchema() error {
+	if g.generated[anySchemaName] != "" {
+		return nil
+	}
+	if err := g.reserveSchema(anySchemaName, "google.protobuf.Any"); err != nil {
+		return err
+	}
+
+	s := ogen.NewSchema().
+		SetType("object").
+		SetDescription("Message of any type, identified by type URL in `@type` property.")
+	s.AddRequiredProperties(&ogen.Property{
+		Name:   anyTypeProperty,
+		Schema: ogen.NewSchema().SetType("string"),
+	})
+	s.AdditionalProperties = &ogen.AdditionalProperties{Schema: ogen.Schema{}}
+	g.spec.AddSchema(anySchemaName, s)
+	return nil
+}
+
+// mkTypedAny returns google.protobuf.Any schema holding message of given type.
+//
+// Message is wrapped to a component with `@type` property and referenced from
+// oneOf with discriminator, so further types could be added.
+func (g *Generator) mkTypedAny(msg *protogen.Message) (*ogen.Schema, error) {
+	var (
+		name    = "AnyOf" + descriptorName(msg.Desc)
+		typeURL = anyTypeURLPrefix + string(msg.Desc.FullName())
+	)
+	if g.generated[name] == "" {
+		if err := g.reserveSchema(name, "google.protobuf.Any of "+string(msg.Desc.FullName())); err != nil {
+			return nil, err
+		}
+
+		typeSchema := ogen.NewSchema().
+			SetType("string").
+			SetEnum([]json.RawMessage{json.RawMessage(strconv.Quote(typeURL))})
+
+		s := ogen.NewSchema().
+			SetType("object").
+			SetDescription(fmt.Sprintf("%s packed into google.protobuf.Any.", msg.Desc.FullName()))
+		s.AddRequiredProperties(&ogen.Property{Name: anyTypeProperty, Schema: typeSchema})
+
+		wkt, ok, err := g.mkWellKnownPrimitive(msg.Desc)
+		switch {
+		case err != nil:
+			return nil, err
+		case ok:
+			// Well-known types with special JSON mapping are stored in "value".
+			s.AddRequiredProperties(&ogen.Property{Name: "value", Schema: wkt})
+		default:
+			if err := g.mkSchema(msg); err != nil {
+				return nil, err
+			}
+			s = ogen.NewSchema().
+				SetDescription(s.Description).
+				SetAllOf([]*ogen.Schema{
+					ogen.NewSchema().SetRef(descriptorRef(msg.Desc)),
+					{
+						Type:       "object",
+						Required:   s.Required,
+						Properties: s.Properties,
+					},
+				})
+		}
+		g.spec.AddSchema(name, s)
+	}
+
+	ref := schemaRef(name)
+	return &ogen.Schema{
+		OneOf: []*ogen.Schema{ogen.NewSchema().SetRef(ref)},
+		Discriminator: &ogen.Discriminator{
+			PropertyName: anyTypeProperty,
+			Mapping:      map[string]string{typeURL: ref},
+		},
+	}, nil
+}
+
+// mkOperationsPaths generates paths of google.longrunning.Operations
+// GetOperation and ListOperations methods under prefix.
+func (g *Generator) mkOperationsPaths() error {
+	ref, err := g.mkGenericOperationSchema()
+	if err != nil {
+		return err
+	}
+
+	operationID := func(method string) string {
+		if g.operationIDNaming == OperationIDNamingServiceMethod {
+			return "operations" + method
+		}
+		return LowerCamelCase(protoreflect.Name(method))
+	}
+
+	// Operation name is "operations/**" as in google.longrunning.Operations
+	// HTTP rules, its segments after the literal form a single parameter.
+	var (
+		listPath = g.operationsPrefix + "/operations"
+		getPath  = listPath + "/{name}"
+	)
+	for _, path := range []string{listPath, getPath} {
+		if _, ok := g.spec.Paths[path]; ok {
+			return errors.Errorf("path %q conflicts with google.longrunning.Operations path", path)
+		}
+	}
+
+	if err := g.reserveSchema(listOperationsResponseName, "google.longrunning.Operations"); err != nil {
+		return err
+	}
+	listResponse := ogen.NewSchema().
+		SetType("object").
+		SetDescription("Response of google.longrunning.Operations.ListOperations.")
+	listResponse.AddOptionalProperties(
+		&ogen.Property{
+			Name:   "operations",
+			Schema: ogen.NewSchema().SetType("array").SetItems(ogen.NewSchema().SetRef(ref)),
+		},
+		&ogen.Property{
+			Name:   "nextPageToken",
+			Schema: ogen.NewSchema().SetType("string").SetDescription("Token to retrieve the next page of results."),
+		},
+	)
+	g.spec.AddSchema(listOperationsResponseName, listResponse)
+
+	pageSize := ogen.NewSchema().
+		SetType("integer").
+		SetFormat("int32").
+		SetDescription("Maximum number of operations to return.")
+	pageSize.Minimum = ogen.Num("0")
+	pageSize.Maximum = ogen.Num(strconv.Itoa(g.maxPageSize))
+
+	list := ogen.NewOperation().
+		SetOperationID(operationID("ListOperations")).
+		SetDescription("Lists operations that match the filter.").
+		AddParameters(
+			ogen.NewParameter().SetIn("query").SetName("filter").
+				SetSchema(ogen.NewSchema().SetType("string").SetDescription("Filter of operations.")),
+			ogen.NewParameter().SetIn("query").SetName("pageSize").SetSchema(pageSize),
+			ogen.NewParameter().SetIn("query").SetName("pageToken").
+				SetSchema(ogen.NewSchema().SetType("string").SetDescription("Page token of previous response.")),
+		).
+		SetResponses(ogen.Responses{
+			"200": ogen.NewResponse().
+				SetDescription("google.longrunning.Operations.ListOperations response").
+				SetJSONContent(ogen.NewSchema().SetRef(schemaRef(listOperationsResponseName))),
+		})
+	setExtension(&list.Common.Extensions, "x-pagination", pagination{
+		PageSize:      "pageSize",
+		PageToken:     "pageToken",
+		NextPageToken: "nextPageToken",
+		Items:         "operations",
+	})
+	g.spec.AddPathItem(listPath, ogen.NewPathItem().SetGet(list))
+
+	get := ogen.NewOperation().
+		SetOperationID(operationID("GetOperation")).
+		SetDescription("Gets the latest state of a long-running operation.").
+		AddParameters(
+			ogen.NewParameter().SetIn("path").SetName("name").SetRequired(true).
+				SetDescription(`Segment of name "operations/{name}".`).
+				SetSchema(ogen.NewSchema().SetType("string").SetPattern(templateRegex("**"))),
+		).
+		SetResponses(ogen.Responses{
+			"200": ogen.NewResponse().
+				SetDescription("google.longrunning.Operations.GetOperation response").
+				SetJSONContent(ogen.NewSchema().SetRef(ref)),
+		})
+	g.spec.AddPathItem(getPath, ogen.NewPathItem().SetGet(get))
+	return nil
+}
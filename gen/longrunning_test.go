@@ -0,0 +1,144 @@
+package gen
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// appendOperationInfo encodes google.longrunning.operation_info option as protoc
+// passes it to the plugin: an unknown field of method options.
+func appendOperationInfo(b []byte, info operationInfo) []byte {
+	var v []byte
+	v = protowire.AppendTag(v, 1, protowire.BytesType)
+	v = protowire.AppendString(v, info.ResponseType)
+	v = protowire.AppendTag(v, 2, protowire.BytesType)
+	v = protowire.AppendString(v, info.MetadataType)
+
+	b = protowire.AppendTag(b, operationInfoNumber, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// setOperationInfos sets operation_info options of request methods by method name.
+func setOperationInfos(req *pluginpb.CodeGeneratorRequest, infos map[string]operationInfo) {
+	for _, f := range req.ProtoFile {
+		for _, s := range f.Service {
+			for _, m := range s.Method {
+				info, ok := infos[m.GetName()]
+				if !ok {
+					continue
+				}
+				opts := m.GetOptions().ProtoReflect()
+				opts.SetUnknown(appendOperationInfo(opts.GetUnknown(), info))
+			}
+		}
+	}
+}
+
+// longrunningInfos are operation_info options of longrunning test case.
+var longrunningInfos = map[string]operationInfo{
+	"ImportBooks":    {ResponseType: "ImportBooksResponse", MetadataType: "library.v1.ImportBooksMetadata"},
+	"EstimateImport": {ResponseType: "google.protobuf.Duration", MetadataType: "ImportBooksMetadata"},
+}
+
+// renameMessage renames message of library.v1 package and references to it.
+func renameMessage(req *pluginpb.CodeGeneratorRequest, from, to string) {
+	rename := func(name *string) {
+		if *name == ".library.v1."+from {
+			*name = ".library.v1." + to
+		}
+	}
+	for _, f := range req.ProtoFile {
+		for _, m := range f.MessageType {
+			if m.GetName() == from {
+				m.Name = &to
+			}
+			for _, field := range m.Field {
+				if field.TypeName != nil {
+					rename(field.TypeName)
+				}
+			}
+		}
+		for _, s := range f.Service {
+			for _, m := range s.Method {
+				rename(m.InputType)
+				rename(m.OutputType)
+			}
+		}
+	}
+}
+
+func TestLongrunningError(t *testing.T) {
+	t.Parallel()
+
+	valid := longrunningInfos["ImportBooks"]
+	for _, tt := range []struct {
+		name    string
+		info    operationInfo
+		rename  [2]string
+		wantErr string
+	}{
+		{"UnknownResponse", operationInfo{ResponseType: "Book", MetadataType: "ImportBooksMetadata"}, [2]string{}, `operation_info: response_type: unknown message "Book"`},
+		{"MissingMetadata", operationInfo{ResponseType: "ImportBooksResponse"}, [2]string{}, "operation_info: metadata_type is required"},
+		{"AnyConflict", valid, [2]string{"PurgeBooksRequest", "Any"}, `schema "Any" conflicts with generated schema of google.protobuf.Any`},
+		{"OperationConflict", valid, [2]string{"PurgeBooksRequest", "Operation"}, `schema "Operation" conflicts with generated schema of google.longrunning.Operation`},
+		{"TypedAnyConflict", valid, [2]string{"PurgeBooksRequest", "AnyOfImportBooksResponse"}, `schema "AnyOfImportBooksResponse" conflicts with generated schema of google.protobuf.Any of library.v1.ImportBooksResponse`},
+		{"ListOperationsResponseConflict", valid, [2]string{"PurgeBooksRequest", "ListOperationsResponse"}, `schema "ListOperationsResponse" of google.longrunning.Operations conflicts with existing schema`},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			textproto, err := os.ReadFile("_testdata/longrunning.textproto")
+			require.NoError(t, err)
+
+			req := new(pluginpb.CodeGeneratorRequest)
+			require.NoError(t, prototext.Unmarshal(textproto, req))
+			setOperationInfos(req, map[string]operationInfo{"ImportBooks": tt.info})
+			if from, to := tt.rename[0], tt.rename[1]; from != "" {
+				renameMessage(req, from, to)
+			}
+
+			p, err := protogen.Options{}.New(req)
+			require.NoError(t, err)
+			for _, f := range p.Files {
+				f.Generate = true
+			}
+
+			_, err = NewGenerator(p.Files, WithOperationsPaths("/v1"))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMethodOperationInfo(t *testing.T) {
+	t.Parallel()
+
+	want := operationInfo{ResponseType: "Book", MetadataType: "OperationMetadata"}
+
+	req := new(pluginpb.CodeGeneratorRequest)
+	require.NoError(t, prototext.Unmarshal([]byte(`proto_file: {
+		name: "service.proto"
+		service: {
+			name: "Service"
+			method: { name: "Get" input_type: ".google.protobuf.Empty" output_type: ".google.protobuf.Empty" options: { deprecated: true } }
+		}
+	}`), req))
+	opts := req.ProtoFile[0].Service[0].Method[0].GetOptions()
+	// Other unknown fields are skipped.
+	b := protowire.AppendTag(nil, 1050, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	opts.ProtoReflect().SetUnknown(appendOperationInfo(b, want))
+
+	got, ok := methodOperationInfo(opts)
+	require.True(t, ok)
+	require.Equal(t, want, got)
+
+	_, ok = methodOperationInfo(nil)
+	require.False(t, ok)
+}
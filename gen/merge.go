@@ -0,0 +1,169 @@
+package gen
+
+import (
+	"reflect"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+	"github.com/ogen-go/ogen/openapi/parser"
+)
+
+// MergePolicy defines how conflicts between base and generated documents are resolved.
+type MergePolicy string
+
+const (
+	// MergePolicyError fails on conflicting definitions.
+	MergePolicyError MergePolicy = "error"
+	// MergePolicyPreferGenerated keeps generated definition.
+	MergePolicyPreferGenerated MergePolicy = "prefer-generated"
+	// MergePolicyPreferBase keeps base definition.
+	MergePolicyPreferBase MergePolicy = "prefer-base"
+)
+
+// mergeBase merges generated paths and components into base document.
+//
+// Path item fields (operations, parameters), components and info fields are
+// merged one by one, same definitions are not conflicts. Other fields set by
+// generator (openapi, servers, security) conflict if they differ.
+func (g *Generator) mergeBase() error {
+	if g.base == nil {
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(g.base, &doc); err != nil {
+		return errors.Wrap(err, "parse base document")
+	}
+
+	generated, err := encodeNode(reflect.ValueOf(g.spec))
+	if err != nil {
+		return errors.Wrap(err, "encode spec")
+	}
+
+	base := &doc
+	if base.Kind == yaml.DocumentNode && len(base.Content) == 1 {
+		base = base.Content[0]
+	}
+	if base.Kind != yaml.MappingNode {
+		return errors.New("base document must be an object")
+	}
+	merged := base
+
+	for i := 0; i+1 < len(generated.Content); i += 2 {
+		key, val := generated.Content[i].Value, generated.Content[i+1]
+
+		to := mappingValue(merged, key)
+		switch {
+		case to == nil:
+			setMappingValue(merged, key, val)
+		case key == "paths" || key == "components":
+			// paths.<path>.<field>, components.<kind>.<name>
+			if err := g.mergeLevel(to, val, key, 2); err != nil {
+				return err
+			}
+		case key == "info":
+			// info.<field>
+			if err := g.mergeLevel(to, val, key, 1); err != nil {
+				return err
+			}
+		case equalNodes(to, val):
+		default:
+			if err := g.resolveConflict(merged, key, val, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return g.setSpecNode(merged)
+}
+
+// setSpecNode replaces specification with decoded document after validation.
+func (g *Generator) setSpecNode(n *yaml.Node) error {
+	spec := new(ogen.Spec)
+	if err := n.Decode(spec); err != nil {
+		return errors.Wrap(err, "decode document")
+	}
+	if _, err := parser.Parse(spec, parser.Settings{}); err != nil {
+		return errors.Wrap(err, "validate document")
+	}
+	g.spec = spec
+
+	return nil
+}
+
+// mergeLevel merges mapping from into mapping to, depth levels deep.
+func (g *Generator) mergeLevel(to, from *yaml.Node, path string, depth int) error {
+	if to.Kind != yaml.MappingNode || from.Kind != yaml.MappingNode {
+		return errors.Errorf("%s: expected object", path)
+	}
+
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		key, val := from.Content[i].Value, from.Content[i+1]
+		keyPath := path + "." + key
+
+		existing := mappingValue(to, key)
+		switch {
+		case existing == nil:
+			setMappingValue(to, key, val)
+		case depth > 1:
+			if err := g.mergeLevel(existing, val, keyPath, depth-1); err != nil {
+				return err
+			}
+		case val.Kind == yaml.ScalarNode && val.Value == "":
+			// Not set by generator, e.g. required info.title.
+		case equalNodes(existing, val):
+		default:
+			if err := g.resolveConflict(to, key, val, keyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveConflict resolves conflicting definition of key according to policy.
+func (g *Generator) resolveConflict(to *yaml.Node, key string, val *yaml.Node, path string) error {
+	switch g.basePolicy {
+	case MergePolicyPreferGenerated:
+		setMappingValue(to, key, val)
+	case MergePolicyPreferBase:
+	default:
+		return errors.Errorf("conflict on %s: defined by both base and generated documents", path)
+	}
+	return nil
+}
+
+func equalNodes(a, b *yaml.Node) bool {
+	a, b = resolveAlias(a), resolveAlias(b)
+	if a.Kind != b.Kind || len(a.Content) != len(b.Content) {
+		return false
+	}
+	if a.Kind == yaml.ScalarNode {
+		return a.Value == b.Value && a.ShortTag() == b.ShortTag()
+	}
+	if a.Kind == yaml.MappingNode {
+		// Key order does not matter.
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			bv := mappingValue(b, a.Content[i].Value)
+			if bv == nil || !equalNodes(a.Content[i+1], bv) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range a.Content {
+		if !equalNodes(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func resolveAlias(n *yaml.Node) *yaml.Node {
+	for n.Kind == yaml.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	return n
+}
@@ -0,0 +1,163 @@
+package gen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+
+	"github.com/ogen-go/ogen"
+)
+
+// unionOneofs returns oneofs of message emitted as discriminated unions.
+//
+// Oneof is a union if all its visible fields are messages and it must be set,
+// i.e. any of its fields is marked as required: JSON Schema oneOf cannot
+// express unset oneof without "not", which ogen does not support.
+func (g *Generator) unionOneofs(msg *protogen.Message) (unions []*protogen.Oneof) {
+	for _, o := range msg.Oneofs {
+		if o.Desc.IsSynthetic() {
+			continue
+		}
+		var (
+			fields   int
+			required bool
+			messages = true
+		)
+		for _, f := range o.Fields {
+			if g.isHiddenField(f) {
+				continue
+			}
+			fields++
+			required = required || isRequired(f.Desc)
+			messages = messages && f.Message != nil && !f.Desc.IsMap()
+		}
+		if fields > 0 && required && messages {
+			unions = append(unions, o)
+		}
+	}
+	return unions
+}
+
+// mkUnion generates variant schemas of oneof and returns oneOf schema of them.
+//
+// Each variant is an object with the only required property named after
+// the oneof field, as protojson encodes it. protojson writes no tag of
+// the set field, so there is no discriminator: ogen distinguishes variants
+// by the unique required property.
+func (g *Generator) mkUnion(msg *protogen.Message, o *protogen.Oneof) (*ogen.Schema, error) {
+	union := &ogen.Schema{}
+	for _, f := range o.Fields {
+		if g.isHiddenField(f) {
+			continue
+		}
+
+		propSchema, err := g.mkFieldSchema(f.Desc, f.Comments.Trailing.String())
+		if err != nil {
+			return nil, errors.Wrapf(err, "make field %q", f.Desc.FullName())
+		}
+
+		name := descriptorName(msg.Desc) + CamelCase(o.Desc.Name()) + CamelCase(f.Desc.Name())
+		if err := g.reserveSchema(name, fmt.Sprintf("oneof field %q", f.Desc.FullName())); err != nil {
+			return nil, err
+		}
+		variant := ogen.NewSchema().
+			SetType("object").
+			SetDescription(fmt.Sprintf("%s with %s set.", msg.Desc.FullName(), f.Desc.JSONName()))
+		variant.AddRequiredProperties(&ogen.Property{
+			Name:   f.Desc.JSONName(),
+			Schema: propSchema,
+		})
+		g.spec.AddSchema(name, variant)
+		g.setSource(jsonPointer("components", "schemas", name), msg.Desc.ParentFile(), f.Location)
+
+		union.OneOf = append(union.OneOf, ogen.NewSchema().SetRef(schemaRef(name)))
+	}
+	return union, nil
+}
+
+// setOneofs annotates message schema with x-oneofs extension listing properties
+// of oneofs which are not unions, at most one of them is set.
+func (g *Generator) setOneofs(s *ogen.Schema, msg *protogen.Message, unions []*protogen.Oneof) {
+	oneofs := make(map[string][]string)
+	for _, o := range msg.Oneofs {
+		if o.Desc.IsSynthetic() || slices.Contains(unions, o) {
+			continue
+		}
+		var props []string
+		for _, f := range o.Fields {
+			if !g.isHiddenField(f) {
+				props = append(props, f.Desc.JSONName())
+			}
+		}
+		if len(props) > 0 {
+			oneofs[string(o.Desc.Name())] = props
+		}
+	}
+	if len(oneofs) > 0 {
+		setExtension(&s.Common.Extensions, "x-oneofs", oneofs)
+	}
+}
+
+// setUnions adds oneof unions to message schema.
+//
+// The only union is set to oneOf of schema, multiple ones are combined with allOf.
+func setUnions(s *ogen.Schema, unions []*ogen.Schema) {
+	switch len(unions) {
+	case 0:
+	case 1:
+		s.OneOf = unions[0].OneOf
+	default:
+		s.AllOf = append(s.AllOf, unions...)
+	}
+}
+
+// schemaUnions returns oneOf variants of schema.
+func schemaUnions(s *ogen.Schema) (unions [][]*ogen.Schema) {
+	if len(s.OneOf) > 0 {
+		unions = append(unions, s.OneOf)
+	}
+	for _, a := range s.AllOf {
+		if a != nil && len(a.OneOf) > 0 {
+			unions = append(unions, a.OneOf)
+		}
+	}
+	return unions
+}
+
+// unionProperties returns properties of oneOf variants set in the example.
+func (g *Generator) unionProperties(s *ogen.Schema, n *yaml.Node, at string) (props []ogen.Property, _ error) {
+	for _, variants := range schemaUnions(s) {
+		var (
+			names   []string
+			matched [][]ogen.Property
+		)
+		for _, v := range variants {
+			if v.Ref != "" {
+				v = g.spec.Components.Schemas[strings.TrimPrefix(v.Ref, schemaRef(""))]
+			}
+			if v == nil || len(v.Required) == 0 {
+				continue
+			}
+			names = append(names, v.Required...)
+			if !slices.ContainsFunc(v.Required, func(req string) bool {
+				return mappingValue(n, req) == nil
+			}) {
+				matched = append(matched, v.Properties)
+			}
+		}
+		switch len(matched) {
+		case 0:
+			return nil, errors.Errorf("%s: one of %q is required", at, names)
+		case 1:
+			props = append(props, matched[0]...)
+		default:
+			return nil, errors.Errorf("%s: only one of %q can be set", at, names)
+		}
+	}
+	return props, nil
+}
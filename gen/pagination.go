@@ -0,0 +1,156 @@
+package gen
+
+import (
+	"reflect"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+)
+
+// DefaultMaxPageSize is default maximum of AIP-158 page_size field.
+const DefaultMaxPageSize = 1000
+
+// pagination is x-pagination extension of AIP-158 list operation.
+//
+// See https://google.aip.dev/158.
+type pagination struct {
+	PageSize      string `yaml:"pageSize"`
+	PageToken     string `yaml:"pageToken"`
+	NextPageToken string `yaml:"nextPageToken"`
+	Items         string `yaml:"items"`
+}
+
+// detectPagination returns pagination fields of method, if it is paginated.
+//
+// Request must have page_size and page_token fields, response must have
+// next_page_token and a repeated or map field of items, the first one is used.
+func detectPagination(m *protogen.Method) (p pagination, pageSize protoreflect.FieldDescriptor, ok bool) {
+	var (
+		in  = m.Input.Desc.Fields()
+		out = m.Output.Desc.Fields()
+	)
+	pageSize = in.ByName("page_size")
+	if pageSize == nil || pageSize.Kind() != protoreflect.Int32Kind || pageSize.IsList() {
+		return p, nil, false
+	}
+	pageToken := in.ByName("page_token")
+	if !isStringField(pageToken) {
+		return p, nil, false
+	}
+	nextPageToken := out.ByName("next_page_token")
+	if !isStringField(nextPageToken) {
+		return p, nil, false
+	}
+
+	for i := 0; i < out.Len(); i++ {
+		f := out.Get(i)
+		if !f.IsList() && !f.IsMap() {
+			continue
+		}
+		return pagination{
+			PageSize:      pageSize.JSONName(),
+			PageToken:     pageToken.JSONName(),
+			NextPageToken: nextPageToken.JSONName(),
+			Items:         f.JSONName(),
+		}, pageSize, true
+	}
+	return p, nil, false
+}
+
+func isStringField(fd protoreflect.FieldDescriptor) bool {
+	return fd != nil && fd.Kind() == protoreflect.StringKind && !fd.IsList()
+}
+
+// pagedRequest is request message schema referenced by bodies of paginated operations.
+type pagedRequest struct {
+	pageSize string // JSON name of page size field.
+	refs     int
+}
+
+// setPagination annotates paginated operation with x-pagination extension
+// and constrains page size.
+func (g *Generator) setPagination(op *ogen.Operation, m *protogen.Method) {
+	if m.Desc.IsStreamingClient() || m.Desc.IsStreamingServer() {
+		return
+	}
+	p, pageSize, ok := detectPagination(m)
+	if !ok {
+		return
+	}
+	setExtension(&op.Common.Extensions, "x-pagination", p)
+
+	if s := g.pageSizeSchema(op, m, pageSize); s != nil {
+		g.constrainPageSize(s)
+	}
+}
+
+func (g *Generator) constrainPageSize(s *ogen.Schema) {
+	s.Minimum = ogen.Num("0")
+	s.Maximum = ogen.Num(strconv.Itoa(g.maxPageSize))
+}
+
+// pageSizeSchema returns schema of page size query parameter or inline request body property.
+//
+// Request message schema referenced by body is constrained by constrainPagedRequests.
+func (g *Generator) pageSizeSchema(op *ogen.Operation, m *protogen.Method, pageSize protoreflect.FieldDescriptor) *ogen.Schema {
+	for _, p := range op.Parameters {
+		if p.In == "query" && p.Name == pageSize.JSONName() {
+			return p.Schema
+		}
+	}
+
+	rb := op.RequestBody
+	if rb == nil {
+		return nil
+	}
+	media, ok := rb.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+	s := media.Schema
+	if s.Ref == descriptorRef(m.Input.Desc) {
+		name := descriptorName(m.Input.Desc)
+		r, ok := g.pagedRequests[name]
+		if !ok {
+			r = &pagedRequest{pageSize: pageSize.JSONName()}
+			g.pagedRequests[name] = r
+		}
+		r.refs++
+		return nil
+	}
+	return findProperty(s, pageSize.JSONName())
+}
+
+// constrainPagedRequests constrains page size of request message schemas
+// which are referenced only by bodies of paginated operations: the schema
+// is shared, so other uses of the message would be constrained too.
+func (g *Generator) constrainPagedRequests() error {
+	if len(g.pagedRequests) == 0 {
+		return nil
+	}
+
+	root, err := encodeNode(reflect.ValueOf(g.spec))
+	if err != nil {
+		return errors.Wrap(err, "encode spec")
+	}
+	refs := make(map[string]int)
+	collectRefs(root, func(ref string) {
+		refs[ref]++
+	})
+
+	for name, r := range g.pagedRequests {
+		s := g.spec.Components.Schemas[name]
+		if s == nil || refs[schemaRef(name)] != r.refs {
+			continue
+		}
+		if p := findProperty(s, r.pageSize); p != nil {
+			g.constrainPageSize(p)
+		}
+	}
+	return nil
+}
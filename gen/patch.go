@@ -0,0 +1,361 @@
+package gen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/errors"
+	"github.com/go-faster/yaml"
+)
+
+// patch is Overlay document or JSON Patch applied to generated specification.
+type patch struct {
+	name string
+	data []byte
+}
+
+// overlay is OpenAPI Overlay 1.0 document.
+//
+// See https://spec.openapis.org/overlay/v1.0.0.html.
+type overlay struct {
+	Overlay string `yaml:"overlay"`
+	Info    struct {
+		Title   string `yaml:"title"`
+		Version string `yaml:"version"`
+	} `yaml:"info"`
+	Extends string          `yaml:"extends"`
+	Actions []overlayAction `yaml:"actions"`
+}
+
+type overlayAction struct {
+	Target      string     `yaml:"target"`
+	Description string     `yaml:"description"`
+	Update      *yaml.Node `yaml:"update"`
+	Remove      bool       `yaml:"remove"`
+}
+
+// jsonPatchOperation is RFC 6902 JSON Patch operation.
+type jsonPatchOperation struct {
+	Op    string     `yaml:"op"`
+	Path  string     `yaml:"path"`
+	From  string     `yaml:"from"`
+	Value *yaml.Node `yaml:"value"`
+}
+
+// applyPatches applies patches to emitted document in order.
+//
+// Patches target the document as it is written, after it is adjusted
+// to target OpenAPI version, e.g. nullable of OpenAPI 3.0 schemas.
+func (g *Generator) applyPatches(root *yaml.Node) error {
+	for _, p := range g.patches {
+		if err := applyPatch(root, p.data); err != nil {
+			return errors.Wrapf(err, "patch %q", p.name)
+		}
+	}
+	return nil
+}
+
+// resetStyle resets style of node and its children to default.
+func resetStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+func applyPatch(root *yaml.Node, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return errors.Wrap(err, "parse")
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 {
+		return errors.New("empty document")
+	}
+	// Values are written in style of generated document, e.g. not as JSON.
+	resetStyle(&doc)
+
+	switch n := doc.Content[0]; {
+	case n.Kind == yaml.SequenceNode:
+		var ops []jsonPatchOperation
+		if err := n.Decode(&ops); err != nil {
+			return errors.Wrap(err, "decode JSON Patch")
+		}
+		for i, op := range ops {
+			if err := op.apply(root); err != nil {
+				return errors.Wrapf(err, "operation %d (%s %s)", i, op.Op, op.Path)
+			}
+		}
+		return nil
+	case mappingValue(n, "overlay") != nil:
+		var o overlay
+		if err := n.Decode(&o); err != nil {
+			return errors.Wrap(err, "decode overlay")
+		}
+		return o.apply(root)
+	default:
+		return errors.New("neither overlay document nor JSON Patch")
+	}
+}
+
+func (o overlay) apply(root *yaml.Node) error {
+	if !strings.HasPrefix(o.Overlay, "1.") {
+		return errors.Errorf("unsupported overlay version %q", o.Overlay)
+	}
+
+	for i, action := range o.Actions {
+		q, err := parseJSONPath(action.Target)
+		if err != nil {
+			return errors.Wrapf(err, "action %d", i)
+		}
+
+		refs := q.eval(root)
+		if len(refs) == 0 {
+			return errors.Errorf("action %d: target %q matches nothing", i, action.Target)
+		}
+
+		if action.Remove {
+			removeNodes(refs)
+			continue
+		}
+		if action.Update == nil {
+			continue
+		}
+		for _, ref := range refs {
+			if err := updateNode(ref.node, action.Update); err != nil {
+				return errors.Wrapf(err, "action %d: target %q", i, action.Target)
+			}
+		}
+	}
+	return nil
+}
+
+// updateNode merges update into target as defined by Overlay specification:
+// objects are merged recursively, values are appended to arrays.
+func updateNode(target, update *yaml.Node) error {
+	switch target.Kind {
+	case yaml.SequenceNode:
+		if update.Kind == yaml.SequenceNode {
+			for _, elem := range update.Content {
+				target.Content = append(target.Content, cloneNode(elem))
+			}
+		} else {
+			target.Content = append(target.Content, cloneNode(update))
+		}
+		return nil
+	case yaml.MappingNode:
+		if update.Kind != yaml.MappingNode {
+			return errors.New("update of object must be an object")
+		}
+		for i := 0; i+1 < len(update.Content); i += 2 {
+			key, val := update.Content[i].Value, update.Content[i+1]
+			if existing := mappingValue(target, key); existing != nil &&
+				existing.Kind == yaml.MappingNode && val.Kind == yaml.MappingNode {
+				if err := updateNode(existing, val); err != nil {
+					return err
+				}
+				continue
+			}
+			setMappingValue(target, key, cloneNode(val))
+		}
+		return nil
+	default:
+		return errors.New("target must be an object or an array")
+	}
+}
+
+// removeNodes removes nodes from their parents.
+func removeNodes(refs []nodeRef) {
+	remove := make(map[*yaml.Node]struct{}, len(refs))
+	for _, ref := range refs {
+		remove[ref.node] = struct{}{}
+	}
+
+	parents := make(map[*yaml.Node]struct{})
+	for _, ref := range refs {
+		if ref.parent == nil {
+			continue
+		}
+		if _, ok := parents[ref.parent]; ok {
+			continue
+		}
+		parents[ref.parent] = struct{}{}
+
+		p := ref.parent
+		content := p.Content[:0]
+		if p.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(p.Content); i += 2 {
+				if _, ok := remove[p.Content[i+1]]; !ok {
+					content = append(content, p.Content[i], p.Content[i+1])
+				}
+			}
+		} else {
+			for _, child := range p.Content {
+				if _, ok := remove[child]; !ok {
+					content = append(content, child)
+				}
+			}
+		}
+		p.Content = content
+	}
+}
+
+func (op jsonPatchOperation) apply(root *yaml.Node) error {
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return errors.New("value is required")
+		}
+	}
+
+	switch op.Op {
+	case "add":
+		return addPointer(root, op.Path, cloneNode(op.Value), false)
+	case "replace":
+		return addPointer(root, op.Path, cloneNode(op.Value), true)
+	case "remove":
+		_, err := removePointer(root, op.Path)
+		return err
+	case "move":
+		val, err := removePointer(root, op.From)
+		if err != nil {
+			return errors.Wrap(err, "from")
+		}
+		return addPointer(root, op.Path, val, false)
+	case "copy":
+		ref, err := resolvePointer(root, op.From)
+		if err != nil {
+			return errors.Wrap(err, "from")
+		}
+		return addPointer(root, op.Path, cloneNode(ref.node), false)
+	case "test":
+		ref, err := resolvePointer(root, op.Path)
+		if err != nil {
+			return err
+		}
+		if !equalNodes(ref.node, op.Value) {
+			return errors.New("test failed")
+		}
+		return nil
+	default:
+		return errors.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// parsePointer parses JSON Pointer (RFC 6901).
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, errors.Errorf("invalid JSON Pointer %q", ptr)
+	}
+
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+func resolvePointer(root *yaml.Node, ptr string) (nodeRef, error) {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return nodeRef{}, err
+	}
+	return resolveTokens(root, tokens)
+}
+
+func resolveTokens(root *yaml.Node, tokens []string) (nodeRef, error) {
+	ref := nodeRef{node: root, index: -1}
+	for _, t := range tokens {
+		n := ref.node
+		switch n.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == t {
+					ref = nodeRef{node: n.Content[i+1], parent: n, index: i + 1}
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nodeRef{}, errors.Errorf("path not found: no key %q", t)
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(t)
+			if err != nil || idx < 0 || idx >= len(n.Content) {
+				return nodeRef{}, errors.Errorf("path not found: no index %q", t)
+			}
+			ref = nodeRef{node: n.Content[idx], parent: n, index: idx}
+		default:
+			return nodeRef{}, errors.Errorf("path not found: %q is not in a container", t)
+		}
+	}
+	return ref, nil
+}
+
+// addPointer adds value at pointer location, replace requires the location to exist.
+func addPointer(root *yaml.Node, ptr string, val *yaml.Node, replace bool) error {
+	tokens, err := parsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return errors.New("document root cannot be replaced")
+	}
+
+	parent, err := resolveTokens(root, tokens[:len(tokens)-1])
+	if err != nil {
+		return err
+	}
+	last := tokens[len(tokens)-1]
+
+	p := parent.node
+	switch p.Kind {
+	case yaml.MappingNode:
+		if replace && mappingValue(p, last) == nil {
+			return errors.Errorf("path not found: no key %q", last)
+		}
+		setMappingValue(p, last, val)
+		return nil
+	case yaml.SequenceNode:
+		if last == "-" && !replace {
+			p.Content = append(p.Content, val)
+			return nil
+		}
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx > len(p.Content) || (replace && idx == len(p.Content)) {
+			return errors.Errorf("path not found: no index %q", last)
+		}
+		if replace {
+			p.Content[idx] = val
+			return nil
+		}
+		p.Content = append(p.Content[:idx], append([]*yaml.Node{val}, p.Content[idx:]...)...)
+		return nil
+	default:
+		return errors.Errorf("path not found: %q is not in a container", last)
+	}
+}
+
+func removePointer(root *yaml.Node, ptr string) (*yaml.Node, error) {
+	ref, err := resolvePointer(root, ptr)
+	if err != nil {
+		return nil, err
+	}
+	if ref.parent == nil {
+		return nil, errors.New("document root cannot be removed")
+	}
+	removeNodes([]nodeRef{ref})
+	return ref.node, nil
+}
+
+func cloneNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = cloneNode(child)
+	}
+	return &c
+}
@@ -2,6 +2,7 @@ package gen
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -14,6 +15,8 @@ type pathTemplate struct {
 type PathSegment struct {
 	Raw   string
 	Param string
+	// Template is a segments template of parameter, e.g. "projects/*" for "{name=projects/*}".
+	Template string
 }
 
 // IsParam whether is segment defines a path parameter.
@@ -67,17 +70,28 @@ func parsePathTemplate(tmpl string) (p pathTemplate, _ error) {
 			}
 			endIdx += i
 
-			// TODO(tdakkota): probably, we can handle some, e.g. /api/v1/{id=users/*}
-			if tmpl[endIdx] == '=' {
-				return p, errAt(endIdx, "subsegments are unsupported")
-			}
-
 			name := tmpl[i:endIdx]
 			if _, ok := names[name]; ok {
 				return p, errAt(i, fmt.Sprintf("parameter %q mapped second time", name))
 			}
 			names[name] = struct{}{}
-			p.Path = append(p.Path, PathSegment{Param: name})
+
+			var template string
+			if tmpl[endIdx] == '=' {
+				start := endIdx + 1
+				end := strings.IndexByte(tmpl[start:], '}')
+				if end < 0 {
+					return p, errAt(len(tmpl), "missing '}'")
+				}
+				end += start
+
+				template = tmpl[start:end]
+				if at, msg, ok := checkSegmentsTemplate(template); !ok {
+					return p, errAt(start+at, msg)
+				}
+				endIdx = end
+			}
+			p.Path = append(p.Path, PathSegment{Param: name, Template: template})
 
 			// Consume '}'
 			i = endIdx + 1
@@ -107,3 +121,46 @@ func parsePathTemplate(tmpl string) (p pathTemplate, _ error) {
 	}
 	return p, nil
 }
+
+// checkSegmentsTemplate checks variable segments template.
+//
+// Returns byte position of invalid segment and error message.
+func checkSegmentsTemplate(template string) (at int, msg string, ok bool) {
+	segments := strings.Split(template, "/")
+	for i, seg := range segments {
+		switch {
+		case seg == "":
+			return at, "empty segment", false
+		case seg == "**":
+			if i != len(segments)-1 {
+				return at, "'**' must be the last segment", false
+			}
+		case seg == "*":
+		case strings.ContainsAny(seg, "*{}="):
+			return at, fmt.Sprintf("invalid segment %q", seg), false
+		}
+		at += len(seg) + 1
+	}
+	return 0, "", true
+}
+
+// templateRegex returns regular expression matching values of segments template.
+func templateRegex(template string) string {
+	var b strings.Builder
+	b.WriteByte('^')
+	for i, seg := range strings.Split(template, "/") {
+		if i > 0 {
+			b.WriteByte('/')
+		}
+		switch seg {
+		case "*":
+			b.WriteString("[^/]+")
+		case "**":
+			b.WriteString(".+")
+		default:
+			b.WriteString(regexp.QuoteMeta(seg))
+		}
+	}
+	b.WriteByte('$')
+	return b.String()
+}
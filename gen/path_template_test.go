@@ -23,10 +23,11 @@ func Test_parsePathTemplate(t *testing.T) {
 		{"/foo/**", pathTemplate{}, "at 5: wildcard patterns are unsupported"},
 		{"/api/v1/{repo}/*", pathTemplate{}, "at 15: wildcard patterns are unsupported"},
 
-		{"/api/v1/{repo=*}", pathTemplate{}, "at 13: subsegments are unsupported"},
-		{"/api/v1/{repo=**}", pathTemplate{}, "at 13: subsegments are unsupported"},
-		{"/api/v1/{repo=/**}", pathTemplate{}, "at 13: subsegments are unsupported"},
-		{"/api/v1/{repo=/issues}", pathTemplate{}, "at 13: subsegments are unsupported"},
+		{"/api/v1/{repo=/**}", pathTemplate{}, "at 14: empty segment"},
+		{"/api/v1/{repo=/issues}", pathTemplate{}, "at 14: empty segment"},
+		{"/api/v1/{repo=**/issues}", pathTemplate{}, "at 14: '**' must be the last segment"},
+		{"/api/v1/{repo=repos/{id}}", pathTemplate{}, `at 20: invalid segment "{id"`},
+		{"/api/v1/{repo=repos/*", pathTemplate{}, "at 21: missing '}'"},
 
 		{"/api/v1/{repo", pathTemplate{}, "at 13: missing '}'"},
 
@@ -61,6 +62,27 @@ func Test_parsePathTemplate(t *testing.T) {
 			},
 			"",
 		},
+		{
+			"/v1/{name=projects/*/books/*}:publish",
+			pathTemplate{
+				Path: []PathSegment{
+					{Raw: "v1/"},
+					{Param: "name", Template: "projects/*/books/*"},
+					{Raw: ":publish"},
+				},
+			},
+			"",
+		},
+		{
+			"/v1/{name=files/**}",
+			pathTemplate{
+				Path: []PathSegment{
+					{Raw: "v1/"},
+					{Param: "name", Template: "files/**"},
+				},
+			},
+			"",
+		},
 	}
 	for i, tt := range tests {
 		tt := tt
@@ -77,6 +99,23 @@ func Test_parsePathTemplate(t *testing.T) {
 	}
 }
 
+func Test_templateRegex(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		template string
+		want     string
+	}{
+		{"*", `^[^/]+$`},
+		{"**", `^.+$`},
+		{"projects/*/books/*", `^projects/[^/]+/books/[^/]+$`},
+		{"files/**", `^files/.+$`},
+		{"v1.0/*", `^v1\.0/[^/]+$`},
+	} {
+		require.Equal(t, tt.want, templateRegex(tt.template), tt.template)
+	}
+}
+
 func FuzzParsePathTemplate(f *testing.F) {
 	for _, s := range []string{
 		"",
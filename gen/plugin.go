@@ -0,0 +1,331 @@
+package gen
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+)
+
+// Output formats.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Params are protoc plugin parameters.
+type Params struct {
+	OpenAPI             string
+	Title               string
+	Description         string
+	Version             string
+	Summary             string
+	TermsOfService      string
+	ContactName         string
+	ContactURL          string
+	ContactEmail        string
+	LicenseName         string
+	LicenseIdentifier   string
+	LicenseURL          string
+	LogoURL             string
+	Base                string
+	BaseConflict        string
+	Patches             []string
+	Examples            string
+	Lint                string
+	LintEnable          []string
+	LintDisable         []string
+	Indent              int
+	Format              string
+	Filename            string
+	StreamContentType   string
+	Streaming           string
+	QueryRecursionLimit int
+	SharedParameters    int
+	FieldOrder          string
+	// Config is path to configuration file, see Config.
+	//
+	// Parameters set explicitly take precedence over the file.
+	Config string
+
+	flags      *flag.FlagSet
+	configOpts []GeneratorOption
+}
+
+// RegisterFlags registers parameters in flag set, using defaults of generator.
+func (p *Params) RegisterFlags(set *flag.FlagSet) {
+	p.flags = set
+	set.StringVar(&p.OpenAPI, "openapi", DefaultOpenAPI, "OpenAPI version")
+	set.StringVar(&p.Title, "title", "", "Title")
+	set.StringVar(&p.Description, "description", "", "Description")
+	set.StringVar(&p.Version, "version", "", "Version")
+	set.StringVar(&p.Summary, "summary", "", "Summary (OpenAPI 3.1)")
+	set.StringVar(&p.TermsOfService, "terms_of_service", "", "Terms of service URL")
+	set.StringVar(&p.ContactName, "contact_name", "", "Contact name")
+	set.StringVar(&p.ContactURL, "contact_url", "", "Contact URL")
+	set.StringVar(&p.ContactEmail, "contact_email", "", "Contact email")
+	set.StringVar(&p.LicenseName, "license_name", "", "License name")
+	set.StringVar(&p.LicenseIdentifier, "license_identifier", "", "License SPDX identifier (OpenAPI 3.1)")
+	set.StringVar(&p.LicenseURL, "license_url", "", "License URL")
+	set.StringVar(&p.LogoURL, "logo_url", "", "Logo URL (x-logo)")
+	set.StringVar(&p.Base, "base", "", "Path to base OpenAPI document to merge generated paths and components into")
+	set.StringVar(&p.BaseConflict, "base_conflict", string(MergePolicyError), "Conflict policy of base merge (error, prefer-generated or prefer-base)")
+	set.Func("patch", "Path to Overlay document or JSON Patch applied after generation, can be repeated", func(s string) error {
+		p.Patches = append(p.Patches, s)
+		return nil
+	})
+	set.StringVar(&p.Examples, "examples", "", "Path to directory with message example files")
+	set.StringVar(&p.Lint, "lint", string(LintLevelWarn), "Lint level (off, warn or error)")
+	set.Func("lint_enable", "Optional lint rule to enable (missing-description or unused-schema), can be repeated", func(s string) error {
+		p.LintEnable = append(p.LintEnable, s)
+		return nil
+	})
+	set.Func("lint_disable", "Lint rule to disable, can be repeated", func(s string) error {
+		p.LintDisable = append(p.LintDisable, s)
+		return nil
+	})
+	set.IntVar(&p.Indent, "indent", 2, "Indent")
+	set.StringVar(&p.Format, "format", FormatYAML, "Format (yaml or json)")
+	set.StringVar(&p.Filename, "filename", "openapi", "Filename")
+	set.StringVar(&p.StreamContentType, "stream_content_type", ContentTypeNDJSON, "Content type of server-streaming responses")
+	set.StringVar(&p.Streaming, "streaming", string(StreamingPolicyError), "Handling of client and bidirectional streaming methods (error or skip)")
+	set.IntVar(&p.QueryRecursionLimit, "query_recursion_limit", 0, "How many times a recursive message is expanded into query parameters")
+	set.IntVar(&p.SharedParameters, "shared_parameters", 0, "Minimal number of operations using identical path or query parameter to share it through components.parameters, 0 disables")
+	set.StringVar(&p.FieldOrder, "field_order", string(FieldOrderDeclaration), "Order of object properties (declaration or number)")
+	set.StringVar(&p.Config, "config", "", "Path to YAML or JSON configuration file")
+}
+
+// LoadConfig reads configuration file, if any, and applies it to parameters
+// which are not set explicitly.
+func (p *Params) LoadConfig() error {
+	if p.Config == "" {
+		return nil
+	}
+
+	c, err := LoadConfig(p.Config)
+	if err != nil {
+		return err
+	}
+	c.apply(p)
+	p.configOpts = c.options()
+
+	return nil
+}
+
+// explicit whether parameter is set explicitly.
+//
+// Without flag set, parameters having non-zero value are considered explicit.
+func (p *Params) explicit(name string, zero bool) bool {
+	if p.flags == nil {
+		return !zero
+	}
+
+	var found bool
+	p.flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+// Options validates parameters and returns corresponding generator options.
+func (p Params) Options() ([]GeneratorOption, error) {
+	switch order := FieldOrder(p.FieldOrder); order {
+	case FieldOrderDeclaration, FieldOrderNumber:
+	default:
+		return nil, errors.Errorf("unknown field order %q", order)
+	}
+	switch ct := p.StreamContentType; ct {
+	case ContentTypeNDJSON, ContentTypeEventStream:
+	default:
+		return nil, errors.Errorf("unsupported stream content type %q", ct)
+	}
+	switch policy := StreamingPolicy(p.Streaming); policy {
+	case StreamingPolicyError, StreamingPolicySkip:
+	default:
+		return nil, errors.Errorf("unknown streaming policy %q", policy)
+	}
+	switch p.Format {
+	case FormatYAML, FormatJSON:
+	default:
+		return nil, errors.Errorf("unknown format %q", p.Format)
+	}
+	switch policy := MergePolicy(p.BaseConflict); policy {
+	case "", MergePolicyError, MergePolicyPreferGenerated, MergePolicyPreferBase:
+	default:
+		return nil, errors.Errorf("unknown base conflict policy %q", policy)
+	}
+	switch level := LintLevel(p.Lint); level {
+	case "", LintLevelOff, LintLevelWarn, LintLevelError:
+	default:
+		return nil, errors.Errorf("unknown lint level %q", level)
+	}
+	for _, rule := range slices.Concat(p.LintEnable, p.LintDisable) {
+		if !slices.Contains(LintRules, LintRule(rule)) {
+			return nil, errors.Errorf("unknown lint rule %q", rule)
+		}
+	}
+	if p.SharedParameters < 0 {
+		return nil, errors.Errorf("shared_parameters must not be negative, got %d", p.SharedParameters)
+	}
+
+	opts := []GeneratorOption{
+		WithSpecOpenAPI(p.OpenAPI),
+		WithSpecInfoTitle(p.Title),
+		WithSpecInfoDescription(p.Description),
+		WithSpecInfoVersion(p.Version),
+		WithIndent(p.Indent),
+		WithFieldOrder(FieldOrder(p.FieldOrder)),
+		WithStreamContentType(p.StreamContentType),
+		WithStreamingPolicy(StreamingPolicy(p.Streaming)),
+		WithQueryRecursionLimit(p.QueryRecursionLimit),
+		WithSharedParameters(p.SharedParameters),
+		WithSpecInfoSummary(p.Summary),
+		WithSpecInfoTermsOfService(p.TermsOfService),
+	}
+	if p.ContactName != "" || p.ContactURL != "" || p.ContactEmail != "" {
+		opts = append(opts, WithSpecInfoContact(ogen.NewContact().
+			SetName(p.ContactName).
+			SetURL(p.ContactURL).
+			SetEmail(p.ContactEmail),
+		))
+	}
+	if p.LicenseName != "" || p.LicenseIdentifier != "" || p.LicenseURL != "" {
+		if p.LicenseName == "" {
+			return nil, errors.New("license name is required")
+		}
+		opts = append(opts, WithSpecInfoLicense(&ogen.License{
+			Name:       p.LicenseName,
+			Identifier: p.LicenseIdentifier,
+			URL:        p.LicenseURL,
+		}))
+	}
+	if p.LogoURL != "" {
+		opts = append(opts, WithSpecInfoExtension("x-logo", map[string]string{"url": p.LogoURL}))
+	}
+	if p.Base != "" {
+		base, err := os.ReadFile(p.Base)
+		if err != nil {
+			return nil, errors.Wrap(err, "read base")
+		}
+		opts = append(opts, WithBase(base, MergePolicy(p.BaseConflict)))
+	}
+	for _, path := range p.Patches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "read patch")
+		}
+		opts = append(opts, WithPatch(path, data))
+	}
+	if p.Examples != "" {
+		opts = append(opts, WithExamples(os.DirFS(p.Examples)))
+	}
+	return append(opts, p.configOpts...), nil
+}
+
+// Check writes lint diagnostics of generated specification to w and validates it.
+//
+// Lint runs first to point to proto definitions of issues ogen parser rejects,
+// generation fails on lint issues if lint level is error.
+func (p Params) Check(g *Generator, w io.Writer) error {
+	var diagnostics []Diagnostic
+	if level := LintLevel(p.Lint); level != LintLevelOff {
+		var disabled []LintRule
+		for _, rule := range LintOptionalRules {
+			if !slices.Contains(p.LintEnable, string(rule)) {
+				disabled = append(disabled, rule)
+			}
+		}
+		for _, rule := range p.LintDisable {
+			disabled = append(disabled, LintRule(rule))
+		}
+
+		var err error
+		diagnostics, err = g.Lint(disabled...)
+		if err != nil {
+			return errors.Wrap(err, "lint")
+		}
+		for _, d := range diagnostics {
+			if _, err := fmt.Fprintln(w, d); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	if LintLevel(p.Lint) == LintLevelError && len(diagnostics) > 0 {
+		return errors.Errorf("lint: %d issue(s) found", len(diagnostics))
+	}
+	return nil
+}
+
+// Output returns name and content of the output file.
+func (p Params) Output(g *Generator) (string, []byte, error) {
+	if p.Format == FormatJSON {
+		data, err := g.JSON()
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s.json", p.Filename), data, nil
+	}
+
+	data, err := g.YAML()
+	if err != nil {
+		return "", nil, err
+	}
+	data = append([]byte("# generated by protoc-gen-oas. DO NOT EDIT\r\n\r\n"), data...)
+	return fmt.Sprintf("%s.yaml", p.Filename), data, nil
+}
+
+// Run generates specification for plugin files and writes output file.
+//
+// Options are applied after ones derived from parameters.
+func Run(plugin *protogen.Plugin, p Params, opts ...GeneratorOption) error {
+	plugin.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL |
+		pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+	plugin.SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	plugin.SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2023
+
+	if err := p.LoadConfig(); err != nil {
+		return err
+	}
+
+	genOpts, err := p.Options()
+	if err != nil {
+		return err
+	}
+	genOpts = append(genOpts, opts...)
+
+	g, err := NewGenerator(plugin.Files, genOpts...)
+	if err != nil {
+		return err
+	}
+
+	if err := p.Check(g, os.Stderr); err != nil {
+		return err
+	}
+
+	name, data, err := p.Output(g)
+	if err != nil {
+		return err
+	}
+
+	gf := plugin.NewGeneratedFile(name, "")
+	if _, err := gf.Write(data); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package gen
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// isRequired whether field is marked as required by label or field behavior.
+func isRequired(fd protoreflect.FieldDescriptor) bool {
+	return fd.Cardinality() == protoreflect.Required || isFieldRequired(fd.Options())
+}
+
+// fieldJSONName returns name of field in JSON object.
+//
+// protojson encodes extensions as "[full.name]" keys.
+func fieldJSONName(fd protoreflect.FieldDescriptor) string {
+	if fd.IsExtension() {
+		return "[" + string(fd.FullName()) + "]"
+	}
+	return fd.JSONName()
+}
+
+// collectExtensions indexes extension fields of all files by extended message.
+func (g *Generator) collectExtensions(files []*protogen.File) {
+	var walk func(msgs []*protogen.Message)
+	walk = func(msgs []*protogen.Message) {
+		for _, m := range msgs {
+			for _, x := range m.Extensions {
+				g.extensions[x.Extendee.Desc.FullName()] = append(g.extensions[x.Extendee.Desc.FullName()], x)
+			}
+			walk(m.Messages)
+		}
+	}
+	for _, f := range files {
+		for _, x := range f.Extensions {
+			g.extensions[x.Extendee.Desc.FullName()] = append(g.extensions[x.Extendee.Desc.FullName()], x)
+		}
+		walk(f.Messages)
+	}
+}
+
+// fieldDefault returns explicit default value of field as protojson encodes it.
+func fieldDefault(fd protoreflect.FieldDescriptor) (json.RawMessage, bool) {
+	if !fd.HasDefault() || fd.IsList() {
+		return nil, false
+	}
+
+	var v any
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		v = fd.DefaultEnumValue().Name()
+	case protoreflect.BytesKind:
+		v = base64.StdEncoding.EncodeToString(fd.Default().Bytes())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f := fd.Default().Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			// Encoded as string, which does not match number schema.
+			return nil, false
+		}
+		bitSize := 64
+		if fd.Kind() == protoreflect.FloatKind {
+			bitSize = 32
+		}
+		return json.RawMessage(strconv.FormatFloat(f, 'g', -1, bitSize)), true
+	default:
+		v = fd.Default().Interface()
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,255 @@
+package gen
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/ogen-go/ogen"
+)
+
+// resourceReference is x-resource-reference extension of field referring to a resource.
+//
+// See https://google.aip.dev/122.
+type resourceReference struct {
+	Type      string `yaml:"type,omitempty"`
+	ChildType string `yaml:"childType,omitempty"`
+}
+
+func messageResource(md protoreflect.MessageDescriptor) (*annotations.ResourceDescriptor, bool) {
+	rd, ok := proto.GetExtension(md.Options(), annotations.E_Resource).(*annotations.ResourceDescriptor)
+	return rd, ok && rd != nil && rd.Type != ""
+}
+
+func fieldResourceReference(fd protoreflect.FieldDescriptor) (*annotations.ResourceReference, bool) {
+	ref, ok := proto.GetExtension(fd.Options(), annotations.E_ResourceReference).(*annotations.ResourceReference)
+	return ref, ok && ref != nil && (ref.Type != "" || ref.ChildType != "")
+}
+
+// collectResources collects resource descriptors of messages and files.
+func (g *Generator) collectResources(files []*protogen.File) {
+	add := func(rd *annotations.ResourceDescriptor) {
+		if rd == nil || rd.Type == "" {
+			return
+		}
+		if _, ok := g.resources[rd.Type]; !ok {
+			g.resources[rd.Type] = rd
+		}
+	}
+
+	var walk func(msgs []*protogen.Message)
+	walk = func(msgs []*protogen.Message) {
+		for _, m := range msgs {
+			if rd, ok := messageResource(m.Desc); ok {
+				add(rd)
+			}
+			walk(m.Messages)
+		}
+	}
+	for _, f := range files {
+		defs, _ := proto.GetExtension(f.Desc.Options(), annotations.E_ResourceDefinition).([]*annotations.ResourceDescriptor)
+		for _, rd := range defs {
+			add(rd)
+		}
+		walk(f.Messages)
+	}
+}
+
+// setResourceType annotates schema of resource message with its type.
+func setResourceType(s *ogen.Schema, md protoreflect.MessageDescriptor) {
+	if rd, ok := messageResource(md); ok {
+		setExtension(&s.Common.Extensions, "x-resource-type", rd.Type)
+	}
+}
+
+// setResourcePattern constrains resource name field and annotates
+// fields referring to resources.
+func (g *Generator) setResourcePattern(s *ogen.Schema, fd protoreflect.FieldDescriptor) {
+	if ref, ok := fieldResourceReference(fd); ok {
+		setExtension(&s.Common.Extensions, "x-resource-reference", resourceReference{
+			Type:      ref.Type,
+			ChildType: ref.ChildType,
+		})
+	}
+	if patterns := g.resourcePatterns(fd); len(patterns) > 0 {
+		s.SetPattern(resourcePatternsRegex(patterns))
+	}
+}
+
+// resourcePatterns returns name patterns of resource field refers to or names.
+func (g *Generator) resourcePatterns(fd protoreflect.FieldDescriptor) []string {
+	if fd.Kind() != protoreflect.StringKind {
+		return nil
+	}
+	if rd, ok := messageResource(fd.ContainingMessage()); ok {
+		nameField := rd.NameField
+		if nameField == "" {
+			nameField = "name"
+		}
+		if string(fd.Name()) == nameField {
+			return rd.Pattern
+		}
+	}
+
+	ref, ok := fieldResourceReference(fd)
+	if !ok {
+		return nil
+	}
+	if ref.Type != "" {
+		// Type "*" refers to any resource.
+		if rd, ok := g.resources[ref.Type]; ok {
+			return rd.Pattern
+		}
+		return nil
+	}
+
+	rd, ok := g.resources[ref.ChildType]
+	if !ok {
+		return nil
+	}
+	var parents []string
+	for _, pattern := range rd.Pattern {
+		if parent, ok := parentPattern(pattern); ok && !slices.Contains(parents, parent) {
+			parents = append(parents, parent)
+		}
+	}
+	return parents
+}
+
+// parentPattern returns pattern of parent resource, e.g. "projects/{project}"
+// for "projects/{project}/books/{book}".
+func parentPattern(pattern string) (string, bool) {
+	segments := strings.Split(pattern, "/")
+	if len(segments) < 4 {
+		// Top-level resource.
+		return "", false
+	}
+	return strings.Join(segments[:len(segments)-2], "/"), true
+}
+
+var resourceVariableRegexp = regexp.MustCompile(`\{[^}]*\}`)
+
+// resourceTemplate converts resource pattern to segments template,
+// e.g. "projects/*/books/*" for "projects/{project}/books/{book}".
+func resourceTemplate(pattern string) string {
+	return resourceVariableRegexp.ReplaceAllStringFunc(pattern, func(v string) string {
+		if strings.HasSuffix(v, "=**}") {
+			return "**"
+		}
+		return "*"
+	})
+}
+
+// resourcePatternsRegex returns regular expression matching any of resource patterns.
+func resourcePatternsRegex(patterns []string) string {
+	alts := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		re := templateRegex(resourceTemplate(pattern))
+		alts[i] = re[1 : len(re)-1]
+	}
+	if len(alts) == 1 {
+		return "^" + alts[0] + "$"
+	}
+	return "^(?:" + strings.Join(alts, "|") + ")$"
+}
+
+// matchTemplate whether path template segments match resource pattern.
+func matchTemplate(template, pattern string) bool {
+	var (
+		tmpl = strings.Split(template, "/")
+		pat  = strings.Split(resourceTemplate(pattern), "/")
+	)
+	for i, seg := range tmpl {
+		if seg == "**" {
+			return len(pat) > i
+		}
+		if i >= len(pat) {
+			return false
+		}
+		switch p := pat[i]; {
+		case p == "**":
+			return false
+		case seg == "*" && p == "*":
+		case seg != p:
+			return false
+		}
+	}
+	return len(tmpl) == len(pat)
+}
+
+// matchPatterns whether segments template matches any of resource patterns.
+func matchPatterns(template string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		return matchTemplate(template, pattern)
+	})
+}
+
+func templateMismatch(field, template string, patterns []string) string {
+	return fmt.Sprintf("segments template %q of %s does not match resource patterns %q", template, field, patterns)
+}
+
+// templateVariable is path parameter of segments template wildcard.
+type templateVariable struct {
+	name     string
+	template string // "*" or "**"
+}
+
+// expandPathTemplate returns OpenAPI path segments of segments template:
+// literals are kept and wildcards become parameters named after variables
+// of matching resource pattern, e.g. "shelves/{shelf}/books/{book}" for
+// "shelves/*/books/*" of Book name.
+//
+// If no resource pattern matches, the only wildcard is named after the
+// field and multiple ones are numbered, ok is false if field has patterns.
+func (g *Generator) expandPathTemplate(fd protoreflect.FieldDescriptor, name, template string) (_ string, vars []templateVariable, ok bool) {
+	var (
+		segments = strings.Split(template, "/")
+		patterns = g.resourcePatterns(fd)
+		names    []string
+	)
+	ok = len(patterns) == 0
+	for _, pattern := range patterns {
+		if matchTemplate(template, pattern) {
+			names = strings.Split(pattern, "/")
+			ok = true
+			break
+		}
+	}
+
+	wildcards := 0
+	for _, seg := range segments {
+		if seg == "*" || seg == "**" {
+			wildcards++
+		}
+	}
+	for i, seg := range segments {
+		if seg != "*" && seg != "**" {
+			continue
+		}
+		v := templateVariable{name: name, template: seg}
+		switch {
+		case names != nil:
+			v.name = LowerCamelCase(patternVariable(names[i]))
+		case wildcards > 1:
+			v.name = name + strconv.Itoa(len(vars)+1)
+		}
+		segments[i] = "{" + v.name + "}"
+		vars = append(vars, v)
+	}
+	return strings.Join(segments, "/"), vars, ok
+}
+
+// patternVariable returns name of resource pattern variable segment, e.g.
+// "book" for "{book}" and "path" for "{path=**}".
+func patternVariable(seg string) string {
+	seg = strings.TrimSuffix(strings.TrimPrefix(seg, "{"), "}")
+	name, _, _ := strings.Cut(seg, "=")
+	return name
+}
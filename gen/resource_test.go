@@ -0,0 +1,39 @@
+package gen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchTemplate(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		template string
+		pattern  string
+		want     bool
+	}{
+		{"shelves/*/books/*", "shelves/{shelf}/books/{book}", true},
+		{"shelves/*", "shelves/{shelf}/books/{book}", false},
+		{"shelves/*/books/*/pages", "shelves/{shelf}/books/{book}", false},
+		{"publishers/*/books/*", "shelves/{shelf}/books/{book}", false},
+		{"shelves/**", "shelves/{shelf}/books/{book}", true},
+		{"**", "shelves/{shelf}", true},
+		{"files/*", "files/{path=**}", false},
+		{"*", "items/{item}", false},
+		{"*", "{item}", true},
+	} {
+		require.Equal(t, tt.want, matchTemplate(tt.template, tt.pattern), "%s ~ %s", tt.template, tt.pattern)
+	}
+}
+
+func Test_resourcePatternsRegex(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `^shelves/[^/]+$`, resourcePatternsRegex([]string{"shelves/{shelf}"}))
+	require.Equal(t,
+		`^(?:shelves/[^/]+/books/[^/]+|publishers/[^/]+/books/[^/]+)$`,
+		resourcePatternsRegex([]string{"shelves/{shelf}/books/{book}", "publishers/{publisher}/books/{book}"}),
+	)
+}
@@ -1,8 +1,10 @@
 package gen
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"google.golang.org/protobuf/compiler/protogen"
@@ -15,13 +17,14 @@ import (
 )
 
 func (g *Generator) mkEnum(e *protogen.Enum) {
-	s := mkEnumOgenSchema(e.Desc)
+	s := g.mkEnumOgenSchema(e.Desc)
 
 	name := descriptorName(e.Desc)
 	g.spec.AddSchema(name, s)
+	g.setSource(jsonPointer("components", "schemas", name), e.Desc.ParentFile(), e.Location)
 }
 
-func enum(ed protoreflect.EnumDescriptor) []json.RawMessage {
+func (g *Generator) enum(ed protoreflect.EnumDescriptor) []json.RawMessage {
 	if ed == nil {
 		return nil
 	}
@@ -30,18 +33,67 @@ func enum(ed protoreflect.EnumDescriptor) []json.RawMessage {
 	enum := make([]json.RawMessage, 0, values.Len())
 
 	for i := 0; i < values.Len(); i++ {
-		val := []byte(values.Get(i).Name())
-		jsonVal, _ := json.Marshal(string(val))
+		v := values.Get(i)
+		if !isCanonicalEnumValue(v) || g.isHiddenEnumValue(v) {
+			continue
+		}
+
+		jsonVal, _ := json.Marshal(string(v.Name()))
 		enum = append(enum, jsonVal)
 	}
 
 	return enum
 }
 
-func mkEnumOgenSchema(ed protoreflect.EnumDescriptor) *ogen.Schema {
+// isCanonicalEnumValue whether value is the first one declared with its number.
+//
+// protojson always encodes the first declared name, so aliases
+// (see allow_alias) are not listed in enum.
+func isCanonicalEnumValue(v protoreflect.EnumValueDescriptor) bool {
+	return v.Parent().(protoreflect.EnumDescriptor).Values().ByNumber(v.Number()) == v
+}
+
+func (g *Generator) mkEnumOgenSchema(ed protoreflect.EnumDescriptor) *ogen.Schema {
 	s := &ogen.Schema{
 		Type: "string",
-		Enum: enum(ed),
+		Enum: g.enum(ed),
+	}
+
+	var (
+		values     = ed.Values()
+		aliases    []string
+		deprecated []string
+	)
+	for i := 0; i < values.Len(); i++ {
+		v := values.Get(i)
+		if g.isHiddenEnumValue(v) {
+			continue
+		}
+
+		if !isCanonicalEnumValue(v) {
+			canonical := values.ByNumber(v.Number())
+			if g.isHiddenEnumValue(canonical) {
+				// Neither is listed in enum.
+				continue
+			}
+			aliases = append(aliases, fmt.Sprintf("- `%s` is an alias of `%s`", v.Name(), canonical.Name()))
+			continue
+		}
+
+		if isDeprecatedEnumValue(v.Options()) {
+			deprecated = append(deprecated, string(v.Name()))
+		}
+	}
+
+	if len(aliases) > 0 {
+		s.SetDescription("Aliases:\n" + strings.Join(aliases, "\n"))
+	}
+	if len(deprecated) > 0 {
+		setExtension(&s.Common.Extensions, "x-deprecated-enum-values", deprecated)
+	}
+	if ed.IsClosed() {
+		// Unknown values are rejected rather than kept as numbers.
+		setExtension(&s.Common.Extensions, "x-enum-closed", true)
 	}
 
 	return s
@@ -51,15 +103,43 @@ func (g *Generator) mkSchema(msg *protogen.Message) error {
 	name := descriptorName(msg.Desc)
 	g.setRef(name)
 
-	if msg.Desc.IsMapEntry() || isInternalMessage(msg.Desc.Options()) {
+	if msg.Desc.IsMapEntry() || g.isHiddenMessage(msg.Desc) {
 		return nil
 	}
 
-	s := ogen.NewSchema().SetType("object")
-
-	if err := g.mkJSONFields(s, msg.Fields); err != nil {
+	description, _, _ := splitExample(string(msg.Comments.Leading))
+	s := ogen.NewSchema().
+		SetType("object").
+		SetDescription(mkCommentText(description))
+
+	var (
+		oneofs = g.unionOneofs(msg)
+		fields = slices.DeleteFunc(slices.Clone(msg.Fields), func(f *protogen.Field) bool {
+			return f.Oneof != nil && slices.Contains(oneofs, f.Oneof)
+		})
+	)
+	fields = append(fields, g.extensions[msg.Desc.FullName()]...)
+	if err := g.mkJSONFields(s, fields); err != nil {
 		return err
 	}
+	var unions []*ogen.Schema
+	for _, o := range oneofs {
+		union, err := g.mkUnion(msg, o)
+		if err != nil {
+			return errors.Wrapf(err, "make oneof %q", o.Desc.FullName())
+		}
+		unions = append(unions, union)
+	}
+	setUnions(s, unions)
+	g.setOneofs(s, msg, oneofs)
+	refineCommonType(msg.Desc, s)
+	setResourceType(s, msg.Desc)
+
+	ptr := jsonPointer("components", "schemas", name)
+	g.setSource(ptr, msg.Desc.ParentFile(), msg.Location)
+	for _, f := range append(msg.Fields, g.extensions[msg.Desc.FullName()]...) {
+		g.setSource(ptr+"/properties/"+escapePointer(fieldJSONName(f.Desc)), f.Desc.ParentFile(), f.Location)
+	}
 
 	for _, field := range msg.Fields {
 		if field.Desc.HasPresence() || field.Desc.IsMap() {
@@ -69,8 +149,7 @@ func (g *Generator) mkSchema(msg *protogen.Message) error {
 		if field.Message != nil {
 			name := descriptorName(field.Desc)
 			if g.hasDescriptorName(name) {
-				s.SetRef(descriptorRef(field.Message.Desc))
-
+				// Field schema already refers to the message.
 				continue
 			}
 
@@ -95,28 +174,76 @@ func (g *Generator) mkSchema(msg *protogen.Message) error {
 		g.mkEnum(e)
 	}
 
+	example, err := g.mkMessageExample(msg)
+	if err != nil {
+		return errors.Wrapf(err, "make example of %q", msg.Desc.FullName())
+	}
+	if example != nil {
+		s.Example = example
+		if err := g.addExample(string(msg.Desc.FullName()), s, example); err != nil {
+			return err
+		}
+	}
+
+	if owner, ok := g.generated[name]; ok {
+		return errors.Errorf("schema %q conflicts with generated schema of %s", name, owner)
+	}
 	g.spec.AddSchema(name, s)
 	return nil
 }
 
 func (g *Generator) mkJSONFields(s *ogen.Schema, fields []*protogen.Field) error {
+	return g.mkPartialJSONFields(s, fields, "", nil)
+}
+
+// mkPartialJSONFields maps fields except bound ones.
+//
+// Messages containing bound fields are inlined without them.
+func (g *Generator) mkPartialJSONFields(s *ogen.Schema, fields []*protogen.Field, protoPrefix string, bound boundFields) error {
+	if g.fieldOrder == FieldOrderNumber {
+		fields = slices.Clone(fields)
+		slices.SortStableFunc(fields, func(a, b *protogen.Field) int {
+			return cmp.Compare(a.Desc.Number(), b.Desc.Number())
+		})
+	}
+
 	for _, f := range fields {
-		isInternalField := isInternalField(f.Desc.Options()) && !isPreviewField(f.Desc.Options())
-		isInternalMessage := f.Message != nil && isInternalMessage(f.Message.Desc.Options())
-		if isInternalField || isInternalMessage {
+		if g.isHiddenField(f) {
 			continue
 		}
 
-		propSchema, err := g.mkFieldSchema(f.Desc, f.Comments.Trailing.String())
-		if err != nil {
-			return errors.Wrapf(err, "make field %q", f.Desc.FullName())
+		protoName := protoPrefix + string(f.Desc.Name())
+		if bound.Has(protoName) {
+			continue
+		}
+
+		var propSchema *ogen.Schema
+		if bound.HasNested(protoName) {
+			propSchema = ogen.NewSchema().
+				SetType("object").
+				SetDeprecated(isDeprecatedField(f.Desc.Options())).
+				SetDescription(mkDescription(f.Comments.Trailing.String()))
+			if err := g.mkPartialJSONFields(propSchema, f.Message.Fields, protoName+".", bound); err != nil {
+				return errors.Wrapf(err, "make field %q", f.Desc.FullName())
+			}
+		} else {
+			var err error
+			propSchema, err = g.mkFieldSchema(f.Desc, f.Comments.Trailing.String())
+			if err != nil {
+				return errors.Wrapf(err, "make field %q", f.Desc.FullName())
+			}
 		}
 
 		prop := ogen.Property{
-			Name:   f.Desc.JSONName(),
+			Name:   fieldJSONName(f.Desc),
 			Schema: propSchema,
 		}
-		if isFieldRequired(f.Desc.Options()) {
+		if !isJSONCompliant(f.Desc.ContainingMessage()) && slices.ContainsFunc(s.Properties, func(p ogen.Property) bool {
+			return p.Name == prop.Name
+		}) {
+			return errors.Errorf("field %q JSON name %q conflicts with another field (json_format is LEGACY_BEST_EFFORT)", f.Desc.FullName(), prop.Name)
+		}
+		if isRequired(f.Desc) {
 			s.AddRequiredProperties(&prop)
 		} else {
 			s.AddOptionalProperties(&prop)
@@ -125,7 +252,8 @@ func (g *Generator) mkJSONFields(s *ogen.Schema, fields []*protogen.Field) error
 	return nil
 }
 
-func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, description string) (s *ogen.Schema, rerr error) {
+func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, comment string) (s *ogen.Schema, rerr error) {
+	description, _, _ := splitExample(comment)
 	defer func() {
 		if rerr != nil {
 			return
@@ -136,6 +264,23 @@ func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, description s
 				SetType("array").
 				SetItems(s)
 		}
+
+		if def, ok := fieldDefault(fd); ok {
+			s.SetDefault(def)
+		}
+
+		example, err := g.mkFieldExample(fd, comment)
+		if err != nil {
+			s, rerr = nil, errors.Wrap(err, "make example")
+			return
+		}
+		if example != nil {
+			s.Example = example
+			if err := g.addExample(string(fd.FullName()), s, example); err != nil {
+				s, rerr = nil, err
+				return
+			}
+		}
 	}()
 
 	switch kind := fd.Kind(); kind {
@@ -168,6 +313,7 @@ func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, description s
 	case protoreflect.StringKind:
 		schema := ogen.NewSchema().SetType("string").SetDeprecated(isDeprecatedField(fd.Options())).SetDescription(mkDescription(description))
 		setFieldFormat(schema, fd.Options())
+		g.setResourcePattern(schema, fd)
 		return schema, nil
 	case protoreflect.BytesKind:
 		// Go's protojson encodes binary data as base64 string.
@@ -180,22 +326,25 @@ func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, description s
 	case protoreflect.EnumKind:
 		return ogen.NewSchema().SetRef(descriptorRef(fd.Enum())).SetDeprecated(isDeprecatedField(fd.Options())).SetDescription(mkDescription(description)), nil
 
-	case protoreflect.MessageKind:
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		// Groups are encoded as nested messages.
 		msg := fd.Message()
 
-		name := descriptorName(msg)
-		g.setRef(name)
-
 		wkt, ok, err := g.mkWellKnownPrimitive(msg)
 		switch {
 		case err != nil:
 			// Unsupported well-known type.
 			return nil, err
 		case ok:
-			// Well-known type.
-			wkt.SetDescription(mkDescription(description))
+			// Well-known type, its description documents the format.
+			wkt.SetDescription(joinDescription(mkDescription(description), wkt.Description))
+			if isFieldMask(msg) {
+				g.setFieldMaskPaths(wkt, fd)
+			}
 			return wkt, nil
 		default:
+			g.setRef(descriptorName(msg))
+
 			if fd.IsMap() {
 				if keyKind := fd.MapKey().Kind(); isUnsupportedMapKeyKind(keyKind) {
 					return nil, errors.Errorf("unsupported map key kind: %s", keyKind)
@@ -226,7 +375,7 @@ func (g *Generator) mkFieldSchema(fd protoreflect.FieldDescriptor, description s
 			// User-defined type.
 			return ogen.NewSchema().SetRef(descriptorRef(msg)).SetDeprecated(isDeprecatedField(fd.Options())).SetDescription(mkDescription(description)), nil
 		}
-	default: // protoreflect.GroupKind
+	default:
 		return nil, errors.Errorf("unsupported kind: %s", kind)
 	}
 }
@@ -263,11 +412,28 @@ func (g *Generator) mkWellKnownPrimitive(msg protoreflect.MessageDescriptor) (s
 			// Do the same here.
 			return ogen.NewSchema().SetType("string").SetFormat("base64").SetNullable(true).SetDeprecated(isDeprecatedField(msg.Options())), true, nil
 
+		case "FieldMask":
+			// protojson encodes paths as a single comma-separated string of camelCase paths.
+			return ogen.NewSchema().
+				SetType("string").
+				SetDescription(fieldMaskDescription).
+				SetPattern(fieldMaskPattern).
+				SetDeprecated(isDeprecatedField(msg.Options())), true, nil
 		case "Duration":
-			return ogen.NewSchema().SetType("string").SetFormat("duration").SetDeprecated(isDeprecatedField(msg.Options())), true, nil
+			// JSON Schema "duration" format is ISO 8601, protojson uses seconds with "s" suffix.
+			s := ogen.NewSchema().
+				SetType("string").
+				SetDescription(durationDescription).
+				SetPattern(durationPattern).
+				SetDeprecated(isDeprecatedField(msg.Options()))
+			setExtension(&s.Common.Extensions, "x-format", "protobuf-duration")
+			return s, true, nil
 		case "Timestamp":
-			// FIXME(tdakkota): protojson uses RFC 3339
-			return ogen.NewSchema().SetType("string").SetFormat("date-time").SetDeprecated(isDeprecatedField(msg.Options())), true, nil
+			return ogen.NewSchema().
+				SetType("string").
+				SetFormat("date-time").
+				SetDescription(timestampDescription).
+				SetDeprecated(isDeprecatedField(msg.Options())), true, nil
 		case "Any",
 			"Value",
 			"NullValue",
@@ -308,6 +474,10 @@ func schemaRef(s string) string {
 	return fmt.Sprintf("#/components/schemas/%s", s)
 }
 
+func requestBodyRef(s string) string {
+	return fmt.Sprintf("#/components/requestBodies/%s", s)
+}
+
 func isDeprecatedMethod(opts protoreflect.ProtoMessage) bool {
 	if opts, ok := opts.(*descriptorpb.MethodOptions); ok && opts != nil && opts.Deprecated != nil {
 		return *opts.Deprecated
@@ -322,6 +492,19 @@ func isDeprecatedField(opts protoreflect.ProtoMessage) bool {
 	return false
 }
 
+func isDeprecatedEnumValue(opts protoreflect.ProtoMessage) bool {
+	if opts, ok := opts.(*descriptorpb.EnumValueOptions); ok && opts != nil && opts.Deprecated != nil {
+		return *opts.Deprecated
+	}
+	return false
+}
+
+// joinDescription joins non-empty description paragraphs.
+func joinDescription(paragraphs ...string) string {
+	paragraphs = slices.DeleteFunc(paragraphs, func(p string) bool { return p == "" })
+	return strings.Join(paragraphs, "\n\n")
+}
+
 func mkDescription(description string) (d string) {
 	d = strings.TrimSpace(description)
 	d = strings.TrimLeft(d, "/ ")
@@ -0,0 +1,90 @@
+package gen
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+)
+
+// shareParameters moves path and query parameters used at least
+// sharedParameters times to components.parameters.
+//
+// Parameters are identical if they have the same name, location, schema and
+// description. Component is named after the parameter, if the name is taken,
+// location and then a number is appended, e.g. "name", "namePath", "namePath2".
+func (g *Generator) shareParameters() error {
+	if g.sharedParameters <= 0 {
+		return nil
+	}
+
+	type group struct {
+		param *ogen.Parameter
+		uses  []**ogen.Parameter
+	}
+	var (
+		groups []*group
+		byKey  = map[string]*group{}
+	)
+	for _, o := range g.sortedOperations() {
+		for i, p := range o.op.Parameters {
+			if p == nil || p.Ref != "" || (p.In != "path" && p.In != "query") {
+				continue
+			}
+			// Encoded form includes extensions.
+			n, err := encodeNode(reflect.ValueOf(p))
+			if err != nil {
+				return errors.Wrapf(err, "encode parameter %q of %s %s", p.Name, o.method, o.path)
+			}
+			var buf bytes.Buffer
+			if err := writeJSON(&buf, n); err != nil {
+				return errors.Wrapf(err, "encode parameter %q of %s %s", p.Name, o.method, o.path)
+			}
+			key := buf.String()
+
+			gr, ok := byKey[key]
+			if !ok {
+				gr = &group{param: p}
+				byKey[key] = gr
+				groups = append(groups, gr)
+			}
+			gr.uses = append(gr.uses, &o.op.Parameters[i])
+		}
+	}
+
+	for _, gr := range groups {
+		if len(gr.uses) < g.sharedParameters {
+			continue
+		}
+		name := g.parameterComponentName(gr.param)
+		g.spec.Components.Parameters[name] = gr.param
+		for _, use := range gr.uses {
+			*use = ogen.NewParameter().SetRef(parameterRef(name))
+		}
+	}
+	return nil
+}
+
+func (g *Generator) parameterComponentName(p *ogen.Parameter) string {
+	taken := func(name string) bool {
+		_, ok := g.spec.Components.Parameters[name]
+		return ok
+	}
+
+	name := p.Name
+	if !taken(name) {
+		return name
+	}
+	name += CamelCase(p.In)
+	if !taken(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		if n := name + strconv.Itoa(i); !taken(n) {
+			return n
+		}
+	}
+}
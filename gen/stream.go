@@ -0,0 +1,151 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/compiler/protogen"
+
+	"github.com/go-faster/errors"
+
+	"github.com/ogen-go/ogen"
+)
+
+const (
+	// ContentTypeNDJSON is a content type of newline-delimited JSON stream.
+	ContentTypeNDJSON = "application/x-ndjson"
+	// ContentTypeEventStream is a content type of Server-Sent Events stream.
+	ContentTypeEventStream = "text/event-stream"
+)
+
+// StreamingPolicy defines how client and bidirectional streaming methods are handled.
+type StreamingPolicy string
+
+const (
+	// StreamingPolicyError fails generation on client or bidirectional streaming method.
+	StreamingPolicyError StreamingPolicy = "error"
+	// StreamingPolicySkip omits client and bidirectional streaming methods from output.
+	StreamingPolicySkip StreamingPolicy = "skip"
+)
+
+// streamErrorSchemaName is a name of google.rpc.Status-like schema of stream error.
+const streamErrorSchemaName = "StreamError"
+
+// checkStreaming returns whether method should be generated.
+func (g *Generator) checkStreaming(m *protogen.Method) (bool, error) {
+	if !m.Desc.IsStreamingClient() {
+		return true, nil
+	}
+
+	switch g.streamingPolicy {
+	case StreamingPolicySkip:
+		return false, nil
+	default:
+		kind := "client"
+		if m.Desc.IsStreamingServer() {
+			kind = "bidirectional"
+		}
+		return false, errors.Errorf("%s streaming method %s is not supported", kind, m.Desc.FullName())
+	}
+}
+
+// mkStreamResponse wraps server-streaming method response schema.
+//
+// grpc-gateway sends every message of a stream as a separate
+// `{"result": ...}` object, and an error as `{"error": ...}`.
+func (g *Generator) mkStreamResponse(m *protogen.Method, body string, result *ogen.Schema) (*ogen.Response, error) {
+	name, err := g.mkStreamResultSchema(m, body, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return ogen.NewResponse().
+		SetDescription(fmt.Sprintf("%s response stream", m.Desc.FullName())).
+		AddContent(g.streamContentType, ogen.NewSchema().SetRef(schemaRef(name))), nil
+}
+
+// mkStreamResultSchema generates stream result wrapper of result schema
+// and returns its name.
+//
+// Wrapper is shared by methods streaming the same result. It is named after
+// the output message, response body field type or the output message and
+// response body field.
+func (g *Generator) mkStreamResultSchema(m *protogen.Method, body string, result *ogen.Schema) (string, error) {
+	n, err := encodeNode(reflect.ValueOf(result))
+	if err != nil {
+		return "", errors.Wrap(err, "encode result schema")
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, n); err != nil {
+		return "", errors.Wrap(err, "encode result schema")
+	}
+	key := buf.String()
+	if name, ok := g.streamResults[key]; ok {
+		return name, nil
+	}
+
+	if err := g.mkStatusSchema(streamErrorSchemaName, "Stream error, see google.rpc.Status."); err != nil {
+		return "", err
+	}
+
+	var (
+		of   = string(m.Output.Desc.FullName())
+		name = "StreamResultOf" + descriptorName(m.Output.Desc)
+	)
+	if body != "" && body != "*" {
+		of += "." + body
+		if ref := strings.TrimPrefix(result.Ref, schemaRef("")); ref != result.Ref {
+			name = "StreamResultOf" + ref
+		} else {
+			name += CamelCase(strings.ReplaceAll(body, ".", "_"))
+		}
+	}
+	for base, i := name, 2; g.hasSchema(name) || g.generated[name] != ""; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	if err := g.reserveSchema(name, "stream results"); err != nil {
+		return "", err
+	}
+
+	s := ogen.NewSchema().
+		SetType("object").
+		SetDescription(fmt.Sprintf("Stream result of %s.", of))
+	s.AddOptionalProperties(
+		&ogen.Property{Name: "result", Schema: result},
+		&ogen.Property{Name: "error", Schema: ogen.NewSchema().SetRef(schemaRef(streamErrorSchemaName))},
+	)
+	g.spec.AddSchema(name, s)
+	g.streamResults[key] = name
+	return name, nil
+}
+
+// mkStatusSchema generates google.rpc.Status-like schema.
+func (g *Generator) mkStatusSchema(name, description string) error {
+	if g.generated[name] != "" {
+		return nil
+	}
+	if err := g.reserveSchema(name, "google.rpc.Status"); err != nil {
+		return err
+	}
+
+	detail := ogen.NewSchema().SetType("object")
+	detail.AddOptionalProperties(&ogen.Property{
+		Name:   "@type",
+		Schema: ogen.NewSchema().SetType("string"),
+	})
+	detail.AdditionalProperties = &ogen.AdditionalProperties{Schema: ogen.Schema{}}
+
+	s := ogen.NewSchema().
+		SetType("object").
+		SetDescription(description)
+	s.AddOptionalProperties(
+		&ogen.Property{Name: "code", Schema: ogen.NewSchema().SetType("integer").SetFormat("int32")},
+		&ogen.Property{Name: "message", Schema: ogen.NewSchema().SetType("string")},
+		&ogen.Property{Name: "details", Schema: ogen.NewSchema().SetType("array").SetItems(detail)},
+	)
+	g.spec.AddSchema(name, s)
+	return nil
+}
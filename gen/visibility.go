@@ -0,0 +1,109 @@
+package gen
+
+import (
+	"slices"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/visibility"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func isInternalField(opts protoreflect.ProtoMessage) bool {
+	return isFieldVisibilityIndicator(opts, "INTERNAL")
+}
+
+func isPreviewField(opts protoreflect.ProtoMessage) bool {
+	return isFieldVisibilityIndicator(opts, "PREVIEW")
+}
+
+func isFieldVisibilityIndicator(opts protoreflect.ProtoMessage, restriction string) bool {
+	return isVisibilityIndicator(opts, visibility.E_FieldVisibility, restriction)
+}
+
+func isInternalMessage(opts protoreflect.ProtoMessage) bool {
+	return isMessageVisibilityIndicator(opts, "INTERNAL")
+}
+
+func isMessageVisibilityIndicator(opts protoreflect.ProtoMessage, restriction string) bool {
+	return isVisibilityIndicator(opts, visibility.E_MessageVisibility, restriction)
+}
+
+func isInternalEnumValue(opts protoreflect.ProtoMessage) bool {
+	return isEnumValueVisibilityIndicator(opts, "INTERNAL")
+}
+
+func isPreviewEnumValue(opts protoreflect.ProtoMessage) bool {
+	return isEnumValueVisibilityIndicator(opts, "PREVIEW")
+}
+
+func isEnumValueVisibilityIndicator(opts protoreflect.ProtoMessage, restriction string) bool {
+	return isVisibilityIndicator(opts, visibility.E_ValueVisibility, restriction)
+}
+
+func isVisibilityIndicator(opts protoreflect.ProtoMessage, ext protoreflect.ExtensionType, restriction string) bool {
+	return slices.Contains(visibilityRestrictions(opts, ext), restriction)
+}
+
+func visibilityRestrictions(opts protoreflect.ProtoMessage, ext protoreflect.ExtensionType) []string {
+	fieldInfo, ok := proto.GetExtension(opts, ext).(*visibility.VisibilityRule)
+	if !ok || fieldInfo == nil || fieldInfo.Restriction == "" {
+		return nil
+	}
+
+	restrictions := strings.Split(fieldInfo.Restriction, ",")
+	for i := range restrictions {
+		restrictions[i] = strings.TrimSpace(restrictions[i])
+	}
+	return restrictions
+}
+
+// isRestricted whether element is hidden by selected visibility labels.
+//
+// Element without restrictions is always visible, otherwise one of
+// its restrictions must be selected.
+func (g *Generator) isRestricted(opts protoreflect.ProtoMessage, ext protoreflect.ExtensionType) bool {
+	restrictions := visibilityRestrictions(opts, ext)
+	if len(restrictions) == 0 {
+		return false
+	}
+	for _, r := range restrictions {
+		if slices.Contains(g.visibilityLabels, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHiddenField whether field or its message type is hidden.
+func (g *Generator) isHiddenField(f *protogen.Field) bool {
+	return g.isHiddenFieldDesc(f.Desc)
+}
+
+func (g *Generator) isHiddenFieldDesc(fd protoreflect.FieldDescriptor) bool {
+	if md := fd.Message(); md != nil && g.isHiddenMessage(md) {
+		return true
+	}
+	opts := fd.Options()
+	if g.visibilityLabels == nil {
+		return isInternalField(opts) && !isPreviewField(opts)
+	}
+	return g.isRestricted(opts, visibility.E_FieldVisibility)
+}
+
+func (g *Generator) isHiddenMessage(md protoreflect.MessageDescriptor) bool {
+	opts := md.Options()
+	if g.visibilityLabels == nil {
+		return isInternalMessage(opts)
+	}
+	return g.isRestricted(opts, visibility.E_MessageVisibility)
+}
+
+func (g *Generator) isHiddenEnumValue(v protoreflect.EnumValueDescriptor) bool {
+	opts := v.Options()
+	if g.visibilityLabels == nil {
+		return isInternalEnumValue(opts) && !isPreviewEnumValue(opts)
+	}
+	return g.isRestricted(opts, visibility.E_ValueVisibility)
+}
@@ -1,2 +0,0 @@
-// Package gen for generate OpenAPI specification from proto.
-package gen
@@ -0,0 +1,7 @@
+// Package oas contains proto options of protoc-gen-oas.
+//
+// Options are declared in oas/options.proto, import it as "oas/options.proto"
+// adding root of this repository to proto import path.
+package oas
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative ../oas/options.proto
@@ -0,0 +1,696 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: oas/options.proto
+
+package oas
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	descriptorpb "google.golang.org/protobuf/types/descriptorpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Info is OpenAPI info object.
+type Info struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Title string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	// Summary is short summary of the API, OpenAPI 3.1 only.
+	Summary        string   `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Description    string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	TermsOfService string   `protobuf:"bytes,4,opt,name=terms_of_service,json=termsOfService,proto3" json:"terms_of_service,omitempty"`
+	Contact        *Contact `protobuf:"bytes,5,opt,name=contact,proto3" json:"contact,omitempty"`
+	License        *License `protobuf:"bytes,6,opt,name=license,proto3" json:"license,omitempty"`
+	Version        string   `protobuf:"bytes,7,opt,name=version,proto3" json:"version,omitempty"`
+	// Extensions are specification extensions, keys must start with "x-".
+	Extensions    map[string]*structpb.Value `protobuf:"bytes,8,rep,name=extensions,proto3" json:"extensions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Info) Reset() {
+	*x = Info{}
+	mi := &file_oas_options_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Info) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Info) ProtoMessage() {}
+
+func (x *Info) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Info.ProtoReflect.Descriptor instead.
+func (*Info) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Info) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Info) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Info) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Info) GetTermsOfService() string {
+	if x != nil {
+		return x.TermsOfService
+	}
+	return ""
+}
+
+func (x *Info) GetContact() *Contact {
+	if x != nil {
+		return x.Contact
+	}
+	return nil
+}
+
+func (x *Info) GetLicense() *License {
+	if x != nil {
+		return x.License
+	}
+	return nil
+}
+
+func (x *Info) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Info) GetExtensions() map[string]*structpb.Value {
+	if x != nil {
+		return x.Extensions
+	}
+	return nil
+}
+
+// Contact is contact information for the API.
+type Contact struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contact) Reset() {
+	*x = Contact{}
+	mi := &file_oas_options_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contact) ProtoMessage() {}
+
+func (x *Contact) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contact.ProtoReflect.Descriptor instead.
+func (*Contact) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Contact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Contact) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Contact) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+// License is license information for the API.
+type License struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Identifier is SPDX license expression, OpenAPI 3.1 only.
+	Identifier    string `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Url           string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *License) Reset() {
+	*x = License{}
+	mi := &file_oas_options_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *License) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*License) ProtoMessage() {}
+
+func (x *License) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use License.ProtoReflect.Descriptor instead.
+func (*License) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *License) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *License) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *License) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+// Schema is OpenAPI options of message schema.
+type Schema struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Example is JSON encoded example of the message.
+	//
+	// Example is parsed with protojson and must be valid for the message.
+	Example       string `protobuf:"bytes,1,opt,name=example,proto3" json:"example,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Schema) Reset() {
+	*x = Schema{}
+	mi := &file_oas_options_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Schema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Schema) ProtoMessage() {}
+
+func (x *Schema) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Schema.ProtoReflect.Descriptor instead.
+func (*Schema) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Schema) GetExample() string {
+	if x != nil {
+		return x.Example
+	}
+	return ""
+}
+
+// Field is OpenAPI options of field schema.
+type Field struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Example is JSON encoded example of the field value, e.g. "\"abc\"" or "[1, 2]".
+	//
+	// Example is parsed with protojson and must be valid for the field.
+	Example       string `protobuf:"bytes,1,opt,name=example,proto3" json:"example,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Field) Reset() {
+	*x = Field{}
+	mi := &file_oas_options_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Field) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Field) ProtoMessage() {}
+
+func (x *Field) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Field.ProtoReflect.Descriptor instead.
+func (*Field) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Field) GetExample() string {
+	if x != nil {
+		return x.Example
+	}
+	return ""
+}
+
+// Operation is OpenAPI options of operation.
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RequestHeaders are header parameters of request, shared through components.parameters.
+	RequestHeaders []*Header `protobuf:"bytes,1,rep,name=request_headers,json=requestHeaders,proto3" json:"request_headers,omitempty"`
+	// ResponseHeaders are headers of response, shared through components.headers.
+	ResponseHeaders []*Header `protobuf:"bytes,2,rep,name=response_headers,json=responseHeaders,proto3" json:"response_headers,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_oas_options_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Operation) GetRequestHeaders() []*Header {
+	if x != nil {
+		return x.RequestHeaders
+	}
+	return nil
+}
+
+func (x *Operation) GetResponseHeaders() []*Header {
+	if x != nil {
+		return x.ResponseHeaders
+	}
+	return nil
+}
+
+// Header is HTTP header.
+type Header struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name is header name, e.g. "Idempotency-Key".
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Required marks request header as required and response header as always sent.
+	Required bool `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	// Format is format of header value, e.g. "uuid".
+	Format string `protobuf:"bytes,4,opt,name=format,proto3" json:"format,omitempty"`
+	// Pattern is regular expression of header value.
+	Pattern       string `protobuf:"bytes,5,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Header) Reset() {
+	*x = Header{}
+	mi := &file_oas_options_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Header) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Header) ProtoMessage() {}
+
+func (x *Header) ProtoReflect() protoreflect.Message {
+	mi := &file_oas_options_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Header.ProtoReflect.Descriptor instead.
+func (*Header) Descriptor() ([]byte, []int) {
+	return file_oas_options_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Header) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Header) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Header) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *Header) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Header) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+var file_oas_options_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*Info)(nil),
+		Field:         51143,
+		Name:          "oas.info",
+		Tag:           "bytes,51143,opt,name=info",
+		Filename:      "oas/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*Operation)(nil),
+		Field:         51143,
+		Name:          "oas.service",
+		Tag:           "bytes,51143,opt,name=service",
+		Filename:      "oas/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*Operation)(nil),
+		Field:         51143,
+		Name:          "oas.operation",
+		Tag:           "bytes,51143,opt,name=operation",
+		Filename:      "oas/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.MessageOptions)(nil),
+		ExtensionType: (*Schema)(nil),
+		Field:         51143,
+		Name:          "oas.schema",
+		Tag:           "bytes,51143,opt,name=schema",
+		Filename:      "oas/options.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*Field)(nil),
+		Field:         51143,
+		Name:          "oas.field",
+		Tag:           "bytes,51143,opt,name=field",
+		Filename:      "oas/options.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// Info is default OpenAPI info object.
+	//
+	// Values set through generator options or parameters take precedence.
+	//
+	// optional oas.Info info = 51143;
+	E_Info = &file_oas_options_proto_extTypes[0]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// Service is OpenAPI options of all operations of service.
+	//
+	// optional oas.Operation service = 51143;
+	E_Service = &file_oas_options_proto_extTypes[1]
+)
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// Operation is OpenAPI options of method operations.
+	//
+	// Headers are merged with service ones, method headers take precedence.
+	//
+	// optional oas.Operation operation = 51143;
+	E_Operation = &file_oas_options_proto_extTypes[2]
+)
+
+// Extension fields to descriptorpb.MessageOptions.
+var (
+	// Schema is OpenAPI options of message schema.
+	//
+	// optional oas.Schema schema = 51143;
+	E_Schema = &file_oas_options_proto_extTypes[3]
+)
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Field is OpenAPI options of field schema.
+	//
+	// optional oas.Field field = 51143;
+	E_Field = &file_oas_options_proto_extTypes[4]
+)
+
+var File_oas_options_proto protoreflect.FileDescriptor
+
+const file_oas_options_proto_rawDesc = "" +
+	"\n" +
+	"\x11oas/options.proto\x12\x03oas\x1a google/protobuf/descriptor.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xfe\x02\n" +
+	"\x04Info\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
+	"\asummary\x18\x02 \x01(\tR\asummary\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12(\n" +
+	"\x10terms_of_service\x18\x04 \x01(\tR\x0etermsOfService\x12&\n" +
+	"\acontact\x18\x05 \x01(\v2\f.oas.ContactR\acontact\x12&\n" +
+	"\alicense\x18\x06 \x01(\v2\f.oas.LicenseR\alicense\x12\x18\n" +
+	"\aversion\x18\a \x01(\tR\aversion\x129\n" +
+	"\n" +
+	"extensions\x18\b \x03(\v2\x19.oas.Info.ExtensionsEntryR\n" +
+	"extensions\x1aU\n" +
+	"\x0fExtensionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\"E\n" +
+	"\aContact\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\"O\n" +
+	"\aLicense\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x02 \x01(\tR\n" +
+	"identifier\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\"\"\n" +
+	"\x06Schema\x12\x18\n" +
+	"\aexample\x18\x01 \x01(\tR\aexample\"!\n" +
+	"\x05Field\x12\x18\n" +
+	"\aexample\x18\x01 \x01(\tR\aexample\"y\n" +
+	"\tOperation\x124\n" +
+	"\x0frequest_headers\x18\x01 \x03(\v2\v.oas.HeaderR\x0erequestHeaders\x126\n" +
+	"\x10response_headers\x18\x02 \x03(\v2\v.oas.HeaderR\x0fresponseHeaders\"\x8c\x01\n" +
+	"\x06Header\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\brequired\x18\x03 \x01(\bR\brequired\x12\x16\n" +
+	"\x06format\x18\x04 \x01(\tR\x06format\x12\x18\n" +
+	"\apattern\x18\x05 \x01(\tR\apattern:=\n" +
+	"\x04info\x12\x1c.google.protobuf.FileOptions\x18Ǐ\x03 \x01(\v2\t.oas.InfoR\x04info:K\n" +
+	"\aservice\x12\x1f.google.protobuf.ServiceOptions\x18Ǐ\x03 \x01(\v2\x0e.oas.OperationR\aservice:N\n" +
+	"\toperation\x12\x1e.google.protobuf.MethodOptions\x18Ǐ\x03 \x01(\v2\x0e.oas.OperationR\toperation:F\n" +
+	"\x06schema\x12\x1f.google.protobuf.MessageOptions\x18Ǐ\x03 \x01(\v2\v.oas.SchemaR\x06schema:A\n" +
+	"\x05field\x12\x1d.google.protobuf.FieldOptions\x18Ǐ\x03 \x01(\v2\n" +
+	".oas.FieldR\x05fieldB+Z)github.com/ogen-go/protoc-gen-oas/oas;oasb\x06proto3"
+
+var (
+	file_oas_options_proto_rawDescOnce sync.Once
+	file_oas_options_proto_rawDescData []byte
+)
+
+func file_oas_options_proto_rawDescGZIP() []byte {
+	file_oas_options_proto_rawDescOnce.Do(func() {
+		file_oas_options_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_oas_options_proto_rawDesc), len(file_oas_options_proto_rawDesc)))
+	})
+	return file_oas_options_proto_rawDescData
+}
+
+var file_oas_options_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_oas_options_proto_goTypes = []any{
+	(*Info)(nil),                        // 0: oas.Info
+	(*Contact)(nil),                     // 1: oas.Contact
+	(*License)(nil),                     // 2: oas.License
+	(*Schema)(nil),                      // 3: oas.Schema
+	(*Field)(nil),                       // 4: oas.Field
+	(*Operation)(nil),                   // 5: oas.Operation
+	(*Header)(nil),                      // 6: oas.Header
+	nil,                                 // 7: oas.Info.ExtensionsEntry
+	(*structpb.Value)(nil),              // 8: google.protobuf.Value
+	(*descriptorpb.FileOptions)(nil),    // 9: google.protobuf.FileOptions
+	(*descriptorpb.ServiceOptions)(nil), // 10: google.protobuf.ServiceOptions
+	(*descriptorpb.MethodOptions)(nil),  // 11: google.protobuf.MethodOptions
+	(*descriptorpb.MessageOptions)(nil), // 12: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 13: google.protobuf.FieldOptions
+}
+var file_oas_options_proto_depIdxs = []int32{
+	1,  // 0: oas.Info.contact:type_name -> oas.Contact
+	2,  // 1: oas.Info.license:type_name -> oas.License
+	7,  // 2: oas.Info.extensions:type_name -> oas.Info.ExtensionsEntry
+	6,  // 3: oas.Operation.request_headers:type_name -> oas.Header
+	6,  // 4: oas.Operation.response_headers:type_name -> oas.Header
+	8,  // 5: oas.Info.ExtensionsEntry.value:type_name -> google.protobuf.Value
+	9,  // 6: oas.info:extendee -> google.protobuf.FileOptions
+	10, // 7: oas.service:extendee -> google.protobuf.ServiceOptions
+	11, // 8: oas.operation:extendee -> google.protobuf.MethodOptions
+	12, // 9: oas.schema:extendee -> google.protobuf.MessageOptions
+	13, // 10: oas.field:extendee -> google.protobuf.FieldOptions
+	0,  // 11: oas.info:type_name -> oas.Info
+	5,  // 12: oas.service:type_name -> oas.Operation
+	5,  // 13: oas.operation:type_name -> oas.Operation
+	3,  // 14: oas.schema:type_name -> oas.Schema
+	4,  // 15: oas.field:type_name -> oas.Field
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	11, // [11:16] is the sub-list for extension type_name
+	6,  // [6:11] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_oas_options_proto_init() }
+func file_oas_options_proto_init() {
+	if File_oas_options_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_oas_options_proto_rawDesc), len(file_oas_options_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 5,
+			NumServices:   0,
+		},
+		GoTypes:           file_oas_options_proto_goTypes,
+		DependencyIndexes: file_oas_options_proto_depIdxs,
+		MessageInfos:      file_oas_options_proto_msgTypes,
+		ExtensionInfos:    file_oas_options_proto_extTypes,
+	}.Build()
+	File_oas_options_proto = out.File
+	file_oas_options_proto_goTypes = nil
+	file_oas_options_proto_depIdxs = nil
+}